@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+)
+
+var ErrListLengths = errors.New("lists have different lengths")
+
+var ErrTooLarge = errors.New("too many locations for an exact pairing")
+
+// Analysis looks at the lists paired up smallest to smallest, as in Part 1.
+type Analysis struct {
+	// distance of each pair, sorted
+	Distances []int
+	// IDs in one list but not the other, sorted and without repeats
+	OnlyLeft, OnlyRight []int
+}
+
+func Analyse(left, right []int) Analysis {
+	left = slices.Sorted(slices.Values(left))
+	right = slices.Sorted(slices.Values(right))
+
+	n := min(len(left), len(right))
+	distances := make([]int, n)
+	for i := range n {
+		distances[i] = abs(left[i] - right[i])
+	}
+	slices.Sort(distances)
+
+	return Analysis{
+		Distances: distances,
+		OnlyLeft:  missingFrom(left, right),
+		OnlyRight: missingFrom(right, left),
+	}
+}
+
+// distinct numbers in sorted a that aren't in sorted b
+func missingFrom(a, b []int) []int {
+	missing := []int{}
+	for i, n := range a {
+		if i > 0 && a[i-1] == n {
+			continue
+		}
+		if _, found := slices.BinarySearch(b, n); !found {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
+
+func (a Analysis) Total() int {
+	total := 0
+	for _, d := range a.Distances {
+		total += d
+	}
+	return total
+}
+
+// Histogram counts distances in buckets of the given width, keyed by the bucket's
+// smallest distance.
+func (a Analysis) Histogram(width int) map[int]int {
+	width = max(width, 1)
+	histogram := make(map[int]int)
+	for _, d := range a.Distances {
+		histogram[d/width*width]++
+	}
+	return histogram
+}
+
+// middle distance, or the mean of the middle two; 0 with no pairs
+func (a Analysis) Median() float64 {
+	n := len(a.Distances)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return float64(a.Distances[n/2])
+	}
+	return float64(a.Distances[n/2-1]+a.Distances[n/2]) / 2
+}
+
+// Percentile is the nearest-rank percentile: the smallest distance at least p% of
+// distances are no larger than. p is clamped to 0-100; 0 with no pairs.
+func (a Analysis) Percentile(p float64) int {
+	n := len(a.Distances)
+	if n == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(min(max(p, 0), 100) / 100 * float64(n)))
+	return a.Distances[max(rank, 1)-1]
+}
+
+func (a Analysis) WriteHistogramCSV(w io.Writer, width int) error {
+	histogram := a.Histogram(width)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"from", "to", "count"})
+	for _, from := range slices.Sorted(maps.Keys(histogram)) {
+		out.Write([]string{strconv.Itoa(from), strconv.Itoa(from + max(width, 1) - 1), strconv.Itoa(histogram[from])})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// what pairing two locations costs
+type CostFunc func(left, right int) int
+
+func AbsoluteCost(left, right int) int {
+	return abs(left - right)
+}
+
+func SquaredCost(left, right int) int {
+	return (left - right) * (left - right)
+}
+
+// absolute distance, but never more than limit
+func CappedCost(limit int) CostFunc {
+	return func(left, right int) int {
+		return min(abs(left-right), limit)
+	}
+}
+
+type Pair struct {
+	Left, Right, Cost int
+}
+
+type Pairing struct {
+	Pairs []Pair
+	Total int
+}
+
+// largest list MinCostPairing takes; the Hungarian algorithm is O(n^3)
+const HUNGARIAN_LIMIT = 1000
+
+// MinCostPairing pairs every left location with a right one so the total cost is as small
+// as possible, using the Hungarian algorithm. Sorting both lists is already optimal for
+// AbsoluteCost and SquaredCost, but not in general, e.g. for CappedCost. Pairs come out
+// in the order of left.
+func MinCostPairing(left, right []int, cost CostFunc) (Pairing, error) {
+	n := len(left)
+	if len(right) != n {
+		return Pairing{}, fmt.Errorf("%w: %v and %v", ErrListLengths, n, len(right))
+	}
+	if n > HUNGARIAN_LIMIT {
+		return Pairing{}, fmt.Errorf("%w: %v, limit %v", ErrTooLarge, n, HUNGARIAN_LIMIT)
+	}
+
+	assignment := hungarian(n, func(i, j int) int { return cost(left[i], right[j]) })
+
+	pairing := Pairing{Pairs: make([]Pair, n)}
+	for i, j := range assignment {
+		c := cost(left[i], right[j])
+		pairing.Pairs[i] = Pair{Left: left[i], Right: right[j], Cost: c}
+		pairing.Total += c
+	}
+	return pairing, nil
+}
+
+// hungarian solves the n x n assignment problem, returning the column for each row. It's
+// the shortest augmenting path form with row and column potentials, 1-indexed inside with
+// column 0 as a sentinel.
+func hungarian(n int, cost func(row, col int) int) []int {
+	u := make([]int, n+1)
+	v := make([]int, n+1)
+	// row matched to each column
+	match := make([]int, n+1)
+	way := make([]int, n+1)
+
+	for row := 1; row <= n; row++ {
+		match[0] = row
+		col := 0
+		minSlack := make([]int, n+1)
+		for j := range minSlack {
+			minSlack[j] = math.MaxInt
+		}
+		used := make([]bool, n+1)
+
+		for match[col] != 0 {
+			used[col] = true
+			i, delta, next := match[col], math.MaxInt, 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				if slack := cost(i-1, j-1) - u[i] - v[j]; slack < minSlack[j] {
+					minSlack[j], way[j] = slack, col
+				}
+				if minSlack[j] < delta {
+					delta, next = minSlack[j], j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[match[j]] += delta
+					v[j] -= delta
+				} else {
+					minSlack[j] -= delta
+				}
+			}
+			col = next
+		}
+
+		// flip the augmenting path
+		for col != 0 {
+			prev := way[col]
+			match[col] = match[prev]
+			col = prev
+		}
+	}
+
+	assignment := make([]int, n)
+	for col := 1; col <= n; col++ {
+		assignment[match[col]-1] = col - 1
+	}
+	return assignment
+}
+
+func (p Pairing) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"left", "right", "cost"})
+	for _, pair := range p.Pairs {
+		out.Write([]string{strconv.Itoa(pair.Left), strconv.Itoa(pair.Right), strconv.Itoa(pair.Cost)})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyse(t *testing.T) {
+	left := []int{3, 4, 2, 1, 3, 3}
+	right := []int{4, 3, 5, 3, 9, 3}
+	a := Analyse(left, right)
+
+	check := func(name string, got, want any) {
+		t.Helper()
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%v: wanted %v, got %v", name, want, got)
+		}
+	}
+
+	check("distances", a.Distances, []int{0, 1, 1, 2, 2, 5})
+	check("total", a.Total(), SumDistances(left, right))
+	check("median", a.Median(), 1.5)
+	check("50th", a.Percentile(50), 1)
+	check("90th", a.Percentile(90), 5)
+	check("0th", a.Percentile(0), 0)
+	check("100th", a.Percentile(100), 5)
+	check("histogram", a.Histogram(2), map[int]int{0: 3, 2: 2, 4: 1})
+	check("only left", a.OnlyLeft, []int{1, 2})
+	check("only right", a.OnlyRight, []int{5, 9})
+	check("not mutated", left, []int{3, 4, 2, 1, 3, 3})
+
+	t.Run("empty", func(t *testing.T) {
+		empty := Analyse(nil, nil)
+		if empty.Median() != 0 || empty.Percentile(50) != 0 || len(empty.OnlyLeft) != 0 {
+			t.Fatalf("wanted zero values, got %+v", empty)
+		}
+	})
+
+	t.Run("histogram CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := a.WriteHistogramCSV(&buf, 2); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := "from,to,count\n0,1,3\n2,3,2\n4,5,1\n"
+		if buf.String() != want {
+			t.Fatalf("wanted %q, got %q", want, buf.String())
+		}
+	})
+}
+
+// cheapest total over every way of pairing up the lists
+func bruteForcePairing(left, right []int, cost CostFunc) int {
+	best := -1
+	var permute func(i int, used []bool, total int)
+	permute = func(i int, used []bool, total int) {
+		if i == len(left) {
+			if best == -1 || total < best {
+				best = total
+			}
+			return
+		}
+		for j := range right {
+			if !used[j] {
+				used[j] = true
+				permute(i+1, used, total+cost(left[i], right[j]))
+				used[j] = false
+			}
+		}
+	}
+	permute(0, make([]bool, len(right)), 0)
+	return best
+}
+
+func TestMinCostPairing(t *testing.T) {
+	t.Run("absolute cost matches Part 1", func(t *testing.T) {
+		left := []int{3, 4, 2, 1, 3, 3}
+		right := []int{4, 3, 5, 3, 9, 3}
+
+		pairing, err := MinCostPairing(left, right, AbsoluteCost)
+		if err != nil || pairing.Total != 11 {
+			t.Fatalf("wanted 11, got %v (%v)", pairing.Total, err)
+		}
+	})
+
+	t.Run("capped cost beats sorting", func(t *testing.T) {
+		left := []int{1, 2, 3}
+		right := []int{2, 3, 100}
+
+		pairing, _ := MinCostPairing(left, right, CappedCost(5))
+		want := []Pair{{1, 100, 5}, {2, 2, 0}, {3, 3, 0}}
+		if pairing.Total != 5 || !reflect.DeepEqual(pairing.Pairs, want) {
+			t.Fatalf("wanted %v, got %v", want, pairing)
+		}
+	})
+
+	t.Run("matches brute force", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		for _, cost := range []CostFunc{AbsoluteCost, SquaredCost, CappedCost(10)} {
+			for range 50 {
+				n := 1 + rng.Intn(6)
+				left, right := make([]int, n), make([]int, n)
+				for i := range n {
+					left[i], right[i] = rng.Intn(30), rng.Intn(30)
+				}
+
+				pairing, _ := MinCostPairing(left, right, cost)
+				if want := bruteForcePairing(left, right, cost); pairing.Total != want {
+					t.Fatalf("%v %v: wanted %v, got %v", left, right, want, pairing.Total)
+				}
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := MinCostPairing([]int{1}, []int{1, 2}, AbsoluteCost); !errors.Is(err, ErrListLengths) {
+			t.Fatalf("wanted ErrListLengths, got %v", err)
+		}
+
+		big := make([]int, HUNGARIAN_LIMIT+1)
+		if _, err := MinCostPairing(big, big, AbsoluteCost); !errors.Is(err, ErrTooLarge) {
+			t.Fatalf("wanted ErrTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		pairing, _ := MinCostPairing([]int{1, 5}, []int{4, 2}, SquaredCost)
+
+		var buf bytes.Buffer
+		if err := pairing.WriteCSV(&buf); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		want := "left,right,cost\n1,2,1\n5,4,1\n"
+		if buf.String() != want {
+			t.Fatalf("wanted %q, got %q", want, buf.String())
+		}
+	})
+}
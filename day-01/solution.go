@@ -3,16 +3,107 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 func main() {
-	part1()
-	part2()
+	generate := flag.Int("generate", 0, "write this many lines of random input to stdout")
+	seed := flag.Int64("seed", 1, "seed for -generate")
+	stream := flag.String("stream", "", "solve this input file with an external sort, for inputs too big for memory")
+	chunkSize := flag.Int("chunk", DEFAULT_CHUNK_SIZE, "lines sorted in memory at a time with -stream")
+	analyse := flag.Bool("analyse", false, "print distance statistics instead of solving the puzzle")
+	pairing := flag.String("pairing", "", "write the cheapest pairing as CSV under this cost: absolute, squared or capped:<limit>")
+	flag.Parse()
+
+	switch {
+	case *analyse:
+		analysis()
+	case *pairing != "":
+		cheapestPairing(*pairing)
+	case *generate > 0:
+		if err := GenerateInput(os.Stdout, *generate, *seed); err != nil {
+			fmt.Println(err)
+		}
+	case *stream != "":
+		streamed(*stream, *chunkSize)
+	default:
+		part1()
+		part2()
+	}
+}
+
+func analysis() {
+	input, _ := GetInput("input.txt")
+	a := Analyse(input.left, input.right)
+
+	fmt.Printf("Pairs: %v, total distance %v\n", len(a.Distances), a.Total())
+	fmt.Printf("Median %v, 90th percentile %v, 99th percentile %v\n", a.Median(), a.Percentile(90), a.Percentile(99))
+	fmt.Printf("Only in left list: %v\nOnly in right list: %v\n", a.OnlyLeft, a.OnlyRight)
+	a.WriteHistogramCSV(os.Stdout, 1000)
+}
+
+func cheapestPairing(name string) {
+	var cost CostFunc
+	switch limit, capped := strings.CutPrefix(name, "capped:"); {
+	case name == "absolute":
+		cost = AbsoluteCost
+	case name == "squared":
+		cost = SquaredCost
+	case capped:
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			fmt.Println("bad cap:", limit)
+			return
+		}
+		cost = CappedCost(n)
+	default:
+		fmt.Println("unknown cost:", name)
+		return
+	}
+
+	input, _ := GetInput("input.txt")
+	pairing, err := MinCostPairing(input.left, input.right, cost)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	pairing.WriteCSV(os.Stdout)
+}
+
+func streamed(filename string, chunkSize int) {
+	file, err := os.Open(filename)
+	if err != nil {
+		fmt.Println(ErrInputFile)
+		return
+	}
+	defer file.Close()
+
+	s, err := StreamInput(file, chunkSize, "")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer s.Close()
+
+	distance, err := s.SumDistances()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Part 1 result: %v\n", distance)
+
+	similarity, err := s.SimilarityScore()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Part 2 result: %v\n", similarity)
 }
 
 func part1() {
@@ -33,6 +124,8 @@ type Input struct {
 
 var ErrInputFile = errors.New("cannot open input file")
 
+var ErrInputLine = errors.New("invalid input line")
+
 func GetInput(filename string) (*Input, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -40,37 +133,66 @@ func GetInput(filename string) (*Input, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return ReadInput(file)
+}
+
+// ReadInput reads two whitespace-separated numbers per line; blank lines are skipped.
+func ReadInput(r io.Reader) (*Input, error) {
+	scanner := bufio.NewScanner(r)
 
 	var left []int
 	var right []int
 
+	lineNumber := 0
 	for scanner.Scan() {
-		line := scanner.Text()
-		items := strings.Split(line, "   ")
+		lineNumber++
+		leftInt, rightInt, ok, err := parseLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %v: %v", ErrInputLine, lineNumber, err)
+		}
+		if !ok {
+			continue
+		}
 
-		leftInt, _ := strconv.Atoi(items[0])
 		left = append(left, leftInt)
-
-		rightInt, _ := strconv.Atoi(items[1])
 		right = append(right, rightInt)
 	}
 
-	return &Input{left: left, right: right}, nil
+	return &Input{left: left, right: right}, scanner.Err()
+}
 
+// ok is false for a blank line
+func parseLine(line string) (left, right int, ok bool, err error) {
+	items := strings.Fields(line)
+	if len(items) == 0 {
+		return 0, 0, false, nil
+	}
+	if len(items) != 2 {
+		return 0, 0, false, fmt.Errorf("want 2 numbers, got %q", line)
+	}
+
+	if left, err = strconv.Atoi(items[0]); err != nil {
+		return 0, 0, false, err
+	}
+	if right, err = strconv.Atoi(items[1]); err != nil {
+		return 0, 0, false, err
+	}
+	return left, right, true, nil
 }
 
 func abs(v int) int {
 	return max(v, -v)
 }
 
+// SumDistances pairs the lists up smallest to smallest and sums the distances between
+// pairs. The lists are left as they are. Extra numbers in the longer list are ignored.
 func SumDistances(left, right []int) int {
-	sort.Ints(left)
-	sort.Ints(right)
+	left = slices.Sorted(slices.Values(left))
+	right = slices.Sorted(slices.Values(right))
 
 	total := 0
 
-	for index, leftNum := range left {
+	for index, leftNum := range left[:min(len(left), len(right))] {
 		distance := abs(leftNum - right[index])
 		total += distance
 	}
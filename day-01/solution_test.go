@@ -2,6 +2,7 @@ package main
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -55,3 +56,35 @@ func TestGetInput(t *testing.T) {
 		}
 	})
 }
+
+func TestReadInput(t *testing.T) {
+	t.Run("any whitespace", func(t *testing.T) {
+		got, err := ReadInput(strings.NewReader("3   4\n4 3\n\n2\t5\n  1    3  \n"))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if SumDistances(got.left, got.right) != 5 || len(got.left) != 4 {
+			t.Fatalf("wanted 4 pairs with distance 5, got %v", got)
+		}
+	})
+
+	t.Run("bad lines", func(t *testing.T) {
+		for _, input := range []string{"1 2 3", "1", "a 2", "1 b"} {
+			if _, err := ReadInput(strings.NewReader(input)); err == nil {
+				t.Fatalf("wanted an error for %q", input)
+			}
+		}
+	})
+}
+
+func TestSumDistancesDoesNotMutate(t *testing.T) {
+	left := []int{3, 4, 2, 1, 3, 3}
+	right := []int{4, 3, 5, 3, 9, 3}
+
+	SumDistances(left, right)
+
+	if left[0] != 3 || right[0] != 4 {
+		t.Fatalf("inputs were sorted in place: %v %v", left, right)
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand"
+	"os"
+	"slices"
+)
+
+// numbers held in memory per run before it's sorted and written out
+const DEFAULT_CHUNK_SIZE = 1 << 22
+
+// most runs merged at once, to keep open files down; more runs than this are merged in
+// passes
+const MAX_FAN_IN = 64
+
+// SortedRuns is a list of numbers too big for memory, held as sorted runs in files. All
+// merges the runs back into one sorted stream.
+type SortedRuns struct {
+	files []string
+	err   error
+}
+
+// writeRun sorts the chunk and writes it to a new file in dir
+func writeRun(dir, prefix string, chunk []int) (string, error) {
+	slices.Sort(chunk)
+	return writeSorted(dir, prefix, slices.Values(chunk))
+}
+
+// writeSorted writes already sorted numbers to a new file in dir as fixed-width
+// little-endian int64s
+func writeSorted(dir, prefix string, numbers iter.Seq[int]) (string, error) {
+	file, err := os.CreateTemp(dir, prefix+"-*.run")
+	if err != nil {
+		return "", err
+	}
+
+	w := bufio.NewWriter(file)
+	buf := make([]byte, 8)
+	for n := range numbers {
+		binary.LittleEndian.PutUint64(buf, uint64(n))
+		if _, err := w.Write(buf); err != nil {
+			file.Close()
+			return "", err
+		}
+	}
+	return file.Name(), errors.Join(w.Flush(), file.Close())
+}
+
+// reduce merges runs MAX_FAN_IN at a time into new runs in dir until there are few
+// enough to merge in one go, removing the merged runs
+func (sr *SortedRuns) reduce(dir, prefix string) error {
+	for len(sr.files) > MAX_FAN_IN {
+		var merged []string
+		for group := range slices.Chunk(sr.files, MAX_FAN_IN) {
+			part := &SortedRuns{files: group}
+			name, err := writeSorted(dir, prefix, part.All())
+			if err = errors.Join(err, part.Err()); err != nil {
+				return err
+			}
+			merged = append(merged, name)
+
+			for _, f := range group {
+				if err := os.Remove(f); err != nil {
+					return err
+				}
+			}
+		}
+		sr.files = merged
+	}
+	return nil
+}
+
+// a run being read back, with its next unread number
+type runReader struct {
+	r    *bufio.Reader
+	buf  [8]byte
+	head int
+}
+
+func (rr *runReader) advance() (bool, error) {
+	if _, err := io.ReadFull(rr.r, rr.buf[:]); err != nil {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	rr.head = int(binary.LittleEndian.Uint64(rr.buf[:]))
+	return true, nil
+}
+
+// min-heap of runs by their next number
+type runHeap []*runReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].head < h[j].head }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *runHeap) Push(x any) {
+	*h = append(*h, x.(*runReader))
+}
+
+func (h *runHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// All is a k-way merge of the runs, holding one number per run in memory. Check Err
+// once it's done.
+func (sr *SortedRuns) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		var h runHeap
+		for _, name := range sr.files {
+			file, err := os.Open(name)
+			if err != nil {
+				sr.err = err
+				return
+			}
+			defer file.Close()
+
+			rr := &runReader{r: bufio.NewReader(file)}
+			ok, err := rr.advance()
+			if err != nil {
+				sr.err = err
+				return
+			}
+			if ok {
+				h = append(h, rr)
+			}
+		}
+		heap.Init(&h)
+
+		for h.Len() > 0 {
+			rr := h[0]
+			if !yield(rr.head) {
+				return
+			}
+
+			ok, err := rr.advance()
+			if err != nil {
+				sr.err = err
+				return
+			}
+			if ok {
+				heap.Fix(&h, 0)
+			} else {
+				heap.Pop(&h)
+			}
+		}
+	}
+}
+
+func (sr *SortedRuns) Err() error {
+	return sr.err
+}
+
+// Streamed holds both lists of a large input as sorted runs on disk.
+type Streamed struct {
+	dir         string
+	left, right *SortedRuns
+}
+
+// StreamInput reads an input of any size, sorting it chunkSize lines at a time into run
+// files under a new directory in tempDir ("" for the system default). Close removes them.
+func StreamInput(r io.Reader, chunkSize int, tempDir string) (*Streamed, error) {
+	if chunkSize < 1 {
+		chunkSize = DEFAULT_CHUNK_SIZE
+	}
+
+	dir, err := os.MkdirTemp(tempDir, "day-01-")
+	if err != nil {
+		return nil, err
+	}
+	s := &Streamed{dir: dir, left: &SortedRuns{}, right: &SortedRuns{}}
+
+	// grown as lines come in, so small inputs don't pay for a whole chunk
+	var left, right []int
+	flush := func() error {
+		if len(left) == 0 {
+			return nil
+		}
+		leftFile, err := writeRun(dir, "left", left)
+		if err != nil {
+			return err
+		}
+		rightFile, err := writeRun(dir, "right", right)
+		if err != nil {
+			return err
+		}
+		s.left.files = append(s.left.files, leftFile)
+		s.right.files = append(s.right.files, rightFile)
+		left, right = left[:0], right[:0]
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		l, r, ok, err := parseLine(scanner.Text())
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("%w: line %v: %v", ErrInputLine, lineNumber, err)
+		}
+		if !ok {
+			continue
+		}
+
+		left, right = append(left, l), append(right, r)
+		if len(left) == chunkSize {
+			if err := flush(); err != nil {
+				s.Close()
+				return nil, err
+			}
+		}
+	}
+	if err := errors.Join(scanner.Err(), flush()); err != nil {
+		s.Close()
+		return nil, err
+	}
+	if err := errors.Join(s.left.reduce(dir, "left"), s.right.reduce(dir, "right")); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Streamed) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// SumDistances is SumDistances over the sorted streams, walking both in step.
+func (s *Streamed) SumDistances() (int, error) {
+	nextRight, stop := iter.Pull(s.right.All())
+	defer stop()
+
+	total := 0
+	for l := range s.left.All() {
+		r, ok := nextRight()
+		if !ok {
+			break
+		}
+		total += abs(l - r)
+	}
+	stop()
+
+	return total, errors.Join(s.left.Err(), s.right.Err())
+}
+
+// SimilarityScore is SimilarityScore over the sorted streams, as a merge join: equal
+// numbers sit together in both, so each right-hand count is only needed once.
+func (s *Streamed) SimilarityScore() (int, error) {
+	nextRight, stop := iter.Pull(s.right.All())
+	defer stop()
+
+	score := 0
+	r, more := nextRight()
+	current, count := 0, 0
+	for l := range s.left.All() {
+		if count > 0 && l == current {
+			score += l * count
+			continue
+		}
+
+		for more && r < l {
+			r, more = nextRight()
+		}
+		current, count = l, 0
+		for more && r == l {
+			count++
+			r, more = nextRight()
+		}
+		score += l * count
+	}
+	stop()
+
+	return score, errors.Join(s.left.Err(), s.right.Err())
+}
+
+// GenerateInput writes lines of random puzzle-like input: two five-digit location IDs
+// per line. The same seed gives the same input.
+func GenerateInput(w io.Writer, lines int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	out := bufio.NewWriter(w)
+
+	for range lines {
+		if _, err := fmt.Fprintf(out, "%v   %v\n", 10000+rng.Intn(90000), 10000+rng.Intn(90000)); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStreamInput(t *testing.T) {
+	check := func(t *testing.T, input string, chunkSize int) {
+		in, err := ReadInput(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		s, err := StreamInput(strings.NewReader(input), chunkSize, t.TempDir())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		defer s.Close()
+
+		distance, err := s.SumDistances()
+		if err != nil || distance != SumDistances(in.left, in.right) {
+			t.Fatalf("wanted distance %v, got %v (%v)", SumDistances(in.left, in.right), distance, err)
+		}
+
+		similarity, err := s.SimilarityScore()
+		if err != nil || similarity != SimilarityScore(in.left, in.right) {
+			t.Fatalf("wanted similarity %v, got %v (%v)", SimilarityScore(in.left, in.right), similarity, err)
+		}
+	}
+
+	t.Run("example", func(t *testing.T) {
+		check(t, "3   4\n4   3\n2   5\n1   3\n3   9\n3   3\n", 2)
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := GenerateInput(&buf, 300, 42); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		// chunks of 1 make more runs than MAX_FAN_IN, so they're merged in passes
+		for _, chunkSize := range []int{1, 7, 100, 300, 0} {
+			check(t, buf.String(), chunkSize)
+		}
+	})
+
+	t.Run("merges in passes", func(t *testing.T) {
+		var buf bytes.Buffer
+		GenerateInput(&buf, MAX_FAN_IN*MAX_FAN_IN+1, 3)
+
+		s, err := StreamInput(&buf, 1, t.TempDir())
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		defer s.Close()
+
+		if len(s.left.files) > MAX_FAN_IN || len(s.right.files) > MAX_FAN_IN {
+			t.Fatalf("wanted at most %v runs, got %v and %v", MAX_FAN_IN, len(s.left.files), len(s.right.files))
+		}
+		entries, _ := os.ReadDir(s.dir)
+		if len(entries) != len(s.left.files)+len(s.right.files) {
+			t.Fatalf("merged runs left behind: %v files", len(entries))
+		}
+	})
+
+	t.Run("repeated numbers", func(t *testing.T) {
+		check(t, "5 5\n5 1\n5 5\n2 2\n1 7\n", 2)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		check(t, "", 3)
+	})
+
+	t.Run("cleans up", func(t *testing.T) {
+		dir := t.TempDir()
+		s, _ := StreamInput(strings.NewReader("1 2\n3 4\n5 6\n"), 1, dir)
+
+		entries, _ := os.ReadDir(s.dir)
+		if len(entries) != 6 {
+			t.Fatalf("wanted 6 run files, got %v", len(entries))
+		}
+
+		s.Close()
+		entries, _ = os.ReadDir(dir)
+		if len(entries) != 0 {
+			t.Fatalf("wanted run files removed, got %v", entries)
+		}
+	})
+
+	t.Run("bad line", func(t *testing.T) {
+		dir := t.TempDir()
+		if _, err := StreamInput(strings.NewReader("1 2\nx\n"), 1, dir); err == nil {
+			t.Fatal("wanted an error")
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Fatalf("wanted run files removed, got %v", entries)
+		}
+	})
+}
+
+func TestGenerateInput(t *testing.T) {
+	var a, b bytes.Buffer
+	GenerateInput(&a, 100, 7)
+	GenerateInput(&b, 100, 7)
+
+	if a.String() != b.String() {
+		t.Fatal("same seed gave different input")
+	}
+	if lines := strings.Count(a.String(), "\n"); lines != 100 {
+		t.Fatalf("wanted 100 lines, got %v", lines)
+	}
+}
+
+func BenchmarkStreamInput(b *testing.B) {
+	var buf bytes.Buffer
+	GenerateInput(&buf, 100000, 1)
+	input := buf.String()
+	dir := b.TempDir()
+
+	for range b.N {
+		s, _ := StreamInput(strings.NewReader(input), 10000, dir)
+		s.SumDistances()
+		s.SimilarityScore()
+		s.Close()
+	}
+}
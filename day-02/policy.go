@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var ErrPolicyConfig = errors.New("invalid policy config")
+
+type Direction int
+
+const (
+	EitherDirection Direction = iota
+	Increasing
+	Decreasing
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Increasing:
+		return "increasing"
+	case Decreasing:
+		return "decreasing"
+	}
+	return "either"
+}
+
+// signs to try when looking for a safe run of levels
+func (d Direction) signs() []int {
+	switch d {
+	case Increasing:
+		return []int{1}
+	case Decreasing:
+		return []int{-1}
+	}
+	return []int{1, -1}
+}
+
+// an extra rule every step between consecutive levels has to pass
+type StepPredicate struct {
+	Name  string
+	Allow func(from, to int) bool
+}
+
+// predicates that can be named in a policy config
+var BuiltinPredicates = map[string]StepPredicate{
+	"non_negative": {
+		Name:  "non_negative",
+		Allow: func(from, to int) bool { return from >= 0 && to >= 0 },
+	},
+}
+
+// SafetyPolicy says how far apart consecutive levels may be, which way they must move,
+// and how many levels may be removed to make a report safe.
+type SafetyPolicy struct {
+	MinStep, MaxStep int
+	Direction        Direction
+	Tolerance        int
+	Predicates       []StepPredicate
+}
+
+// the puzzle's rules: strictly increasing or decreasing by 1-3, no removals
+var DefaultPolicy = SafetyPolicy{MinStep: 1, MaxStep: 3, Direction: EitherDirection}
+
+type Reason string
+
+const (
+	ReasonNone         Reason = ""
+	ReasonDirection    Reason = "wrong direction"
+	ReasonStepTooSmall Reason = "step too small"
+	ReasonStepTooLarge Reason = "step too large"
+)
+
+// Verdict on a single report. FirstViolation is the index of the first level whose step
+// from the previous level breaks the policy (-1 if none), regardless of tolerance.
+type Verdict struct {
+	SafetyResult
+	FirstViolation int
+	Reason         Reason
+}
+
+func (v Verdict) String() string {
+	var sb strings.Builder
+	if v.Safe {
+		sb.WriteString("safe")
+	} else {
+		sb.WriteString("unsafe")
+	}
+
+	if v.FirstViolation != -1 {
+		fmt.Fprintf(&sb, ", first violation at level %v: %v", v.FirstViolation, v.Reason)
+	}
+	if len(v.Removed) > 0 {
+		fmt.Fprintf(&sb, ", remove levels %v", v.Removed)
+	}
+	return sb.String()
+}
+
+func (p SafetyPolicy) Check(levels []int) Verdict {
+	var verdict Verdict
+	verdict.FirstViolation, verdict.Reason = p.firstViolation(levels)
+
+	for _, sign := range p.Direction.signs() {
+		removed, ok := p.minRemovals(levels, sign)
+		if ok && (!verdict.Safe || len(removed) < len(verdict.Removed)) {
+			verdict.Safe, verdict.Removed = true, removed
+		}
+	}
+
+	return verdict
+}
+
+func (p SafetyPolicy) CheckAll(reports [][]int) []Verdict {
+	var verdicts []Verdict
+	for _, levels := range reports {
+		verdicts = append(verdicts, p.Check(levels))
+	}
+	return verdicts
+}
+
+// why the step from levels[from] to levels[to] breaks the policy when moving in sign's direction
+func (p SafetyPolicy) stepViolation(levels []int, from, to, sign int) Reason {
+	step := (levels[to] - levels[from]) * sign
+
+	switch {
+	case step < 0:
+		return ReasonDirection
+	case step < p.MinStep:
+		return ReasonStepTooSmall
+	case step > p.MaxStep:
+		return ReasonStepTooLarge
+	}
+
+	for _, predicate := range p.Predicates {
+		if !predicate.Allow(levels[from], levels[to]) {
+			return Reason("failed " + predicate.Name)
+		}
+	}
+	return ReasonNone
+}
+
+func (p SafetyPolicy) firstViolation(levels []int) (int, Reason) {
+	sign := p.Direction.signs()[0]
+
+	// with no required direction, the first change decides it
+	if p.Direction == EitherDirection {
+		for i := 1; i < len(levels); i++ {
+			if levels[i] < levels[i-1] {
+				sign = -1
+			}
+			if levels[i] != levels[i-1] {
+				break
+			}
+		}
+	}
+
+	for i := 1; i < len(levels); i++ {
+		if reason := p.stepViolation(levels, i-1, i, sign); reason != ReasonNone {
+			return i, reason
+		}
+	}
+	return -1, ReasonNone
+}
+
+// minRemovals finds the fewest removals leaving every step valid when moving in sign's
+// direction. cost[i] is the fewest removals before i given that level i is kept; the
+// previous kept level is at most Tolerance+1 back, so this is O(n * Tolerance).
+func (p SafetyPolicy) minRemovals(levels []int, sign int) ([]int, bool) {
+	n := len(levels)
+	if n == 0 {
+		return []int{}, true
+	}
+
+	cost := make([]int, n)
+	prev := make([]int, n)
+	bestEnd, bestCost := -1, p.Tolerance+1
+
+	for i := range n {
+		// drop everything before i
+		cost[i], prev[i] = i, -1
+
+		for j := max(0, i-p.Tolerance-1); j < i; j++ {
+			c := cost[j] + (i - j - 1)
+			if c < cost[i] && p.stepViolation(levels, j, i, sign) == ReasonNone {
+				cost[i], prev[i] = c, j
+			}
+		}
+
+		// drop everything after i
+		if total := cost[i] + (n - 1 - i); total < bestCost {
+			bestEnd, bestCost = i, total
+		}
+	}
+
+	if bestEnd == -1 {
+		return nil, false
+	}
+
+	kept := make([]bool, n)
+	for i := bestEnd; i != -1; i = prev[i] {
+		kept[i] = true
+	}
+
+	removed := []int{}
+	for i, k := range kept {
+		if !k {
+			removed = append(removed, i)
+		}
+	}
+	return removed, true
+}
+
+func LoadPolicy(filename string, predicates map[string]StepPredicate) (*SafetyPolicy, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, ErrInputFile
+	}
+	defer file.Close()
+
+	return ParsePolicy(file, predicates)
+}
+
+// ParsePolicy reads `key = value` lines, starting from DefaultPolicy. Blank lines and
+// lines starting with # are ignored. Keys are min_step, max_step, direction
+// (increasing, decreasing or either), tolerance, and predicates (comma-separated
+// names looked up in predicates).
+func ParsePolicy(r io.Reader, predicates map[string]StepPredicate) (*SafetyPolicy, error) {
+	policy := DefaultPolicy
+	scanner := bufio.NewScanner(r)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: line %v: expected key = value", ErrPolicyConfig, lineNumber)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "min_step":
+			policy.MinStep, err = strconv.Atoi(value)
+		case "max_step":
+			policy.MaxStep, err = strconv.Atoi(value)
+		case "tolerance":
+			policy.Tolerance, err = strconv.Atoi(value)
+		case "direction":
+			policy.Direction, err = parseDirection(value)
+		case "predicates":
+			policy.Predicates, err = lookupPredicates(value, predicates)
+		default:
+			err = fmt.Errorf("unknown key %q", key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %v: %w", ErrPolicyConfig, lineNumber, err)
+		}
+	}
+
+	if policy.MinStep > policy.MaxStep {
+		return nil, fmt.Errorf("%w: min_step %v is above max_step %v", ErrPolicyConfig, policy.MinStep, policy.MaxStep)
+	}
+	if policy.Tolerance < 0 {
+		return nil, fmt.Errorf("%w: negative tolerance", ErrPolicyConfig)
+	}
+
+	return &policy, scanner.Err()
+}
+
+func parseDirection(value string) (Direction, error) {
+	for _, d := range []Direction{EitherDirection, Increasing, Decreasing} {
+		if d.String() == value {
+			return d, nil
+		}
+	}
+	return EitherDirection, fmt.Errorf("unknown direction %q", value)
+}
+
+func lookupPredicates(value string, predicates map[string]StepPredicate) ([]StepPredicate, error) {
+	var found []StepPredicate
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		predicate, ok := predicates[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown predicate %q", name)
+		}
+		found = append(found, predicate)
+	}
+	return found, nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSafetyPolicy(t *testing.T) {
+	t.Run("default policy matches the puzzle", func(t *testing.T) {
+		reports := [][]int{
+			{7, 6, 4, 2, 1},
+			{1, 2, 7, 8, 9},
+			{9, 7, 6, 2, 1},
+			{1, 3, 2, 4, 5},
+			{8, 6, 4, 4, 1},
+			{1, 3, 6, 7, 9},
+		}
+
+		want := []Verdict{
+			{SafetyResult{Safe: true, Removed: []int{}}, -1, ReasonNone},
+			{SafetyResult{Safe: false}, 2, ReasonStepTooLarge},
+			{SafetyResult{Safe: false}, 3, ReasonStepTooLarge},
+			{SafetyResult{Safe: false}, 2, ReasonDirection},
+			{SafetyResult{Safe: false}, 3, ReasonStepTooSmall},
+			{SafetyResult{Safe: true, Removed: []int{}}, -1, ReasonNone},
+		}
+
+		got := DefaultPolicy.CheckAll(reports)
+
+		CheckEqual(got, want, t)
+	})
+
+	t.Run("required direction", func(t *testing.T) {
+		policy := SafetyPolicy{MinStep: 1, MaxStep: 3, Direction: Increasing}
+
+		got := policy.Check([]int{7, 6, 4, 2, 1})
+
+		CheckEqual(got.Safe, false, t)
+		CheckEqual(got.FirstViolation, 1, t)
+		CheckEqual(got.Reason, ReasonDirection, t)
+	})
+
+	t.Run("wider limits and tolerance", func(t *testing.T) {
+		policy := SafetyPolicy{MinStep: 0, MaxStep: 10, Direction: EitherDirection, Tolerance: 1}
+
+		CheckEqual(policy.Check([]int{10, 10, 20, 25}).Safe, true, t)
+		CheckEqual(policy.Check([]int{10, 30, 20, 25}).Removed, []int{1}, t)
+		CheckEqual(policy.Check([]int{10, 30, 50, 60}).Safe, false, t)
+	})
+
+	t.Run("custom predicates", func(t *testing.T) {
+		policy := DefaultPolicy
+		policy.Predicates = []StepPredicate{BuiltinPredicates["non_negative"]}
+
+		got := policy.Check([]int{1, 0, -1, -2})
+
+		CheckEqual(got.Safe, false, t)
+		CheckEqual(got.FirstViolation, 2, t)
+		CheckEqual(got.Reason, Reason("failed non_negative"), t)
+	})
+
+	t.Run("String()", func(t *testing.T) {
+		policy := DefaultPolicy
+		policy.Tolerance = 1
+
+		CheckEqual(policy.Check([]int{1, 3, 6, 7, 9}).String(), "safe", t)
+		CheckEqual(policy.Check([]int{1, 2, 7, 8, 9}).String(), "unsafe, first violation at level 2: step too large", t)
+		CheckEqual(policy.Check([]int{1, 2, 7, 3, 4}).String(), "safe, first violation at level 2: step too large, remove levels [2]", t)
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Run("reads every key", func(t *testing.T) {
+		config := `
+# sensor limits
+min_step = 0
+max_step=25
+direction = decreasing
+tolerance = 2
+predicates = non_negative
+`
+		got, err := ParsePolicy(strings.NewReader(config), BuiltinPredicates)
+
+		CheckEqual(err, nil, t)
+		CheckEqual(got.MinStep, 0, t)
+		CheckEqual(got.MaxStep, 25, t)
+		CheckEqual(got.Direction, Decreasing, t)
+		CheckEqual(got.Tolerance, 2, t)
+		CheckEqual(len(got.Predicates), 1, t)
+		CheckEqual(got.Predicates[0].Name, "non_negative", t)
+	})
+
+	t.Run("missing keys keep puzzle defaults", func(t *testing.T) {
+		got, _ := ParsePolicy(strings.NewReader("tolerance = 1"), nil)
+
+		want := DefaultPolicy
+		want.Tolerance = 1
+
+		CheckEqual(*got, want, t)
+	})
+
+	t.Run("rejects bad configs", func(t *testing.T) {
+		for _, config := range []string{
+			"min_step",
+			"min_step = one",
+			"direction = sideways",
+			"predicates = unknown",
+			"colour = blue",
+			"min_step = 5\nmax_step = 2",
+			"tolerance = -1",
+		} {
+			_, err := ParsePolicy(strings.NewReader(config), BuiltinPredicates)
+			CheckEqual(errors.Is(err, ErrPolicyConfig), true, t)
+		}
+	})
+}
+
+func TestLoadPolicy(t *testing.T) {
+	t.Run("loads from file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "policy.txt")
+		os.WriteFile(filename, []byte("max_step = 5\n"), 0o644)
+
+		got, err := LoadPolicy(filename, nil)
+
+		CheckEqual(err, nil, t)
+		CheckEqual(got.MaxStep, 5, t)
+	})
+
+	t.Run("returns err on bad path", func(t *testing.T) {
+		_, err := LoadPolicy("missing.txt", nil)
+
+		CheckEqual(err, ErrInputFile, t)
+	})
+}
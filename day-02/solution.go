@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"slices"
@@ -13,10 +14,31 @@ import (
 )
 
 func main() {
+	policyFile := flag.String("policy", "", "check reports against a safety policy config instead of solving the puzzle")
+	flag.Parse()
+
+	if *policyFile != "" {
+		checkWithPolicy(*policyFile)
+		return
+	}
+
 	part1()
 	part2()
 }
 
+func checkWithPolicy(policyFile string) {
+	policy, err := LoadPolicy(policyFile, BuiltinPredicates)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	input, _ := GetInput("input.txt")
+	for i, verdict := range policy.CheckAll(input.reports) {
+		fmt.Printf("report %v: %v\n", i, verdict)
+	}
+}
+
 func part1() {
 	input, _ := GetInput("input.txt")
 	result := ReportSafetyCheck(input.reports)
@@ -76,18 +98,18 @@ func ReportSafetyCheckWithTolerance(reports [][]int) []bool {
 	var results []bool
 
 	for _, levels := range reports {
-		result := isSafe(levels)
-		if !result {
-			permuts := GetPermutationsWithOneMissing(levels)
-			for _, permut := range permuts {
-				if isSafe(permut) {
-					result = true
-					break
-				}
-			}
-		}
+		results = append(results, CheckSafety(levels, 1).Safe)
+	}
 
-		results = append(results, result)
+	return results
+}
+
+// return the SafetyResult of each report when up to tolerance levels may be removed
+func ReportSafetyCheckWithRemovals(reports [][]int, tolerance int) []SafetyResult {
+	var results []SafetyResult
+
+	for _, levels := range reports {
+		results = append(results, CheckSafety(levels, tolerance))
 	}
 
 	return results
@@ -105,14 +127,23 @@ func ReportSafetyCheck(reports [][]int) []bool {
 	return results
 }
 
-var atLeast1 = func(i int) bool { return i >= 1 }
-var atMost3 = func(i int) bool { return i <= 3 }
-
 func isSafe(levels []int) bool {
-	distances := GetDistances(levels)
-	return IsIncreasingOrDecreasing(levels) &&
-		utils.All(distances, atLeast1) &&
-		utils.All(distances, atMost3)
+	return DefaultPolicy.Check(levels).Safe
+}
+
+// outcome of a tolerant safety check; Removed holds the level indices that have to go
+// (empty if the report is safe as-is, nil if it can't be made safe)
+type SafetyResult struct {
+	Safe    bool
+	Removed []int
+}
+
+// CheckSafety works out the fewest levels to remove to make the report safe under the
+// puzzle's rules, giving up if that's more than tolerance. Levels are not modified.
+func CheckSafety(levels []int, tolerance int) SafetyResult {
+	policy := DefaultPolicy
+	policy.Tolerance = tolerance
+	return policy.Check(levels).SafetyResult
 }
 
 func GetPermutationsWithOneMissing(levels []int) [][]int {
@@ -141,7 +172,7 @@ func IsIncreasingOrDecreasing(levels []int) bool {
 		return true
 	}
 
-	slices.Reverse(levels)
-	isDecreasing := slices.IsSorted(levels)
+	// compare backwards rather than reversing, so the caller's levels are left alone
+	isDecreasing := slices.IsSortedFunc(levels, func(a, b int) int { return b - a })
 	return isDecreasing
 }
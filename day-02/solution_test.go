@@ -2,6 +2,7 @@ package main
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -134,3 +135,69 @@ func CheckEqual[K any](got, want K, t testing.TB) {
 		t.Fatalf("wanted %v, got %v", want, got)
 	}
 }
+
+func TestCheckSafety(t *testing.T) {
+	t.Run("safe report needs no removals", func(t *testing.T) {
+		want := SafetyResult{Safe: true, Removed: []int{}}
+		got := CheckSafety([]int{7, 6, 4, 2, 1}, 1)
+
+		CheckEqual(got, want, t)
+	})
+
+	t.Run("reports a level to remove", func(t *testing.T) {
+		for _, levels := range [][]int{{1, 3, 2, 4, 5}, {8, 6, 4, 4, 1}} {
+			got := CheckSafety(levels, 1)
+			CheckEqual(len(got.Removed), 1, t)
+
+			index := got.Removed[0]
+			remaining := slices.Delete(slices.Clone(levels), index, index+1)
+			CheckEqual(isSafe(remaining), true, t)
+		}
+	})
+
+	t.Run("first and last levels can be removed", func(t *testing.T) {
+		CheckEqual(CheckSafety([]int{9, 1, 2, 3}, 1), SafetyResult{Safe: true, Removed: []int{0}}, t)
+		CheckEqual(CheckSafety([]int{1, 2, 3, 9}, 1), SafetyResult{Safe: true, Removed: []int{3}}, t)
+	})
+
+	t.Run("unsafe beyond tolerance", func(t *testing.T) {
+		want := SafetyResult{Safe: false}
+		got := CheckSafety([]int{1, 2, 7, 8, 9}, 1)
+
+		CheckEqual(got, want, t)
+	})
+
+	t.Run("general tolerance", func(t *testing.T) {
+		levels := []int{1, 2, 7, 8, 9}
+
+		CheckEqual(CheckSafety(levels, 2), SafetyResult{Safe: true, Removed: []int{0, 1}}, t)
+		CheckEqual(CheckSafety(levels, 0), SafetyResult{Safe: false}, t)
+		CheckEqual(CheckSafety([]int{5, 1, 2, 9, 9, 3, 4}, 3), SafetyResult{Safe: true, Removed: []int{0, 3, 4}}, t)
+	})
+
+	t.Run("does not modify levels", func(t *testing.T) {
+		levels := []int{9, 7, 8, 6}
+		CheckSafety(levels, 1)
+		IsIncreasingOrDecreasing(levels)
+
+		CheckEqual(levels, []int{9, 7, 8, 6}, t)
+	})
+
+	t.Run("agrees with brute force for one removal", func(t *testing.T) {
+		reports := [][]int{
+			{7, 6, 4, 2, 1}, {1, 2, 7, 8, 9}, {9, 7, 6, 2, 1}, {1, 3, 2, 4, 5},
+			{8, 6, 4, 4, 1}, {1, 3, 6, 7, 9}, {48, 46, 47, 49, 51, 54, 56},
+			{1, 1, 2, 3, 4, 5}, {1, 2, 3, 4, 5, 5}, {5, 1, 2, 3, 4, 5}, {1, 4, 3, 2, 1},
+			{1, 6, 7, 8, 9}, {1, 2, 3, 4, 3}, {9, 8, 7, 6, 7}, {7, 10, 8, 10, 11},
+		}
+
+		for _, levels := range reports {
+			want := isSafe(levels)
+			for _, permut := range GetPermutationsWithOneMissing(levels) {
+				want = want || isSafe(permut)
+			}
+
+			CheckEqual(CheckSafety(levels, 1).Safe, want, t)
+		}
+	})
+}
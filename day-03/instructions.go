@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+var (
+	ErrInvalidInstruction   = errors.New("invalid instruction")
+	ErrDuplicateInstruction = errors.New("instruction already registered")
+)
+
+// how each argument of an instruction is written
+type ArgGrammar struct {
+	MinDigits, MaxDigits int
+	// allow a leading '-'
+	Signed bool
+}
+
+// 1-3 unsigned digits, as the puzzle's mul() uses
+var DefaultArgGrammar = ArgGrammar{MinDigits: 1, MaxDigits: 3}
+
+// Instruction describes `name(arg,arg,...)` with Arity arguments.
+//
+// Eval gives the value of an enabled instruction; nil means it contributes nothing.
+// Toggle maps the current disabled depth to the new one; nil means it leaves it alone.
+// Instructions count as enabled while the depth is 0.
+type Instruction struct {
+	Name   string
+	Arity  int
+	Arg    ArgGrammar
+	Eval   func(args []int) int
+	Toggle func(depth int) int
+}
+
+func (i Instruction) literal() string {
+	return i.Name + "("
+}
+
+// an ordered registry of instructions for the Tokenizer to recognise
+type InstructionSet struct {
+	instructions []*Instruction
+}
+
+func NewInstructionSet(instructions ...Instruction) (*InstructionSet, error) {
+	set := &InstructionSet{}
+	for _, i := range instructions {
+		if err := set.Register(i); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+func (s *InstructionSet) Register(i Instruction) error {
+	if i.Name == "" || strings.ContainsAny(i.Name, "(),") || i.Arity < 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidInstruction, i.Name)
+	}
+	if s.Get(i.Name) != nil {
+		return fmt.Errorf("%w: %q", ErrDuplicateInstruction, i.Name)
+	}
+
+	if i.Arg == (ArgGrammar{}) {
+		i.Arg = DefaultArgGrammar
+	}
+	if i.Arg.MinDigits < 1 || i.Arg.MaxDigits < i.Arg.MinDigits {
+		return fmt.Errorf("%w: %q has bad argument grammar %+v", ErrInvalidInstruction, i.Name, i.Arg)
+	}
+
+	s.instructions = append(s.instructions, &i)
+	return nil
+}
+
+func (s *InstructionSet) Get(name string) *Instruction {
+	for _, i := range s.instructions {
+		if i.Name == name {
+			return i
+		}
+	}
+	return nil
+}
+
+func (s *InstructionSet) Names() []string {
+	return utils.Map(s.instructions, func(i *Instruction) string { return i.Name })
+}
+
+var (
+	MulInstruction = Instruction{
+		Name:  "mul",
+		Arity: 2,
+		Eval:  func(args []int) int { return args[0] * args[1] },
+	}
+	AddInstruction = Instruction{
+		Name:  "add",
+		Arity: 2,
+		Eval:  func(args []int) int { return args[0] + args[1] },
+	}
+	DoInstruction = Instruction{
+		Name:   "do",
+		Toggle: func(int) int { return 0 },
+	}
+	DontInstruction = Instruction{
+		Name:   "don't",
+		Toggle: func(int) int { return 1 },
+	}
+)
+
+// mul(), do() and don't(), as the puzzle describes
+func DefaultInstructionSet() *InstructionSet {
+	set, _ := NewInstructionSet(MulInstruction, DoInstruction, DontInstruction)
+	return set
+}
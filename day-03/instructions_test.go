@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestInstructionSet(t *testing.T) {
+	t.Run("Register() rejects duplicates", func(t *testing.T) {
+		set := DefaultInstructionSet()
+		err := set.Register(MulInstruction)
+
+		utils.CheckEqual(errors.Is(err, ErrDuplicateInstruction), true, t)
+	})
+
+	t.Run("Register() rejects bad names and grammars", func(t *testing.T) {
+		set := DefaultInstructionSet()
+
+		for _, i := range []Instruction{
+			{Name: ""},
+			{Name: "mu(l"},
+			{Name: "neg", Arity: -1},
+			{Name: "big", Arity: 1, Arg: ArgGrammar{MinDigits: 4, MaxDigits: 2}},
+		} {
+			err := set.Register(i)
+			utils.CheckEqual(errors.Is(err, ErrInvalidInstruction), true, t)
+		}
+	})
+
+	t.Run("Register() defaults the argument grammar", func(t *testing.T) {
+		set, _ := NewInstructionSet(AddInstruction)
+
+		utils.CheckEqual(set.Get("add").Arg, DefaultArgGrammar, t)
+	})
+
+	t.Run("Names() keeps registration order", func(t *testing.T) {
+		utils.CheckEqual(DefaultInstructionSet().Names(), []string{"mul", "do", "don't"}, t)
+	})
+}
+
+func TestCustomInstructions(t *testing.T) {
+	t.Run("add() alongside mul()", func(t *testing.T) {
+		set := DefaultInstructionSet()
+		set.Register(AddInstruction)
+
+		part1, part2, _ := SolveWith(strings.NewReader("add(2,3)don't()mul(2,3)add(1,1)do()add(4,4)"), set)
+
+		utils.CheckEqual(part1, 5+6+2+8, t)
+		utils.CheckEqual(part2, 5+8, t)
+	})
+
+	t.Run("arity and signed arguments", func(t *testing.T) {
+		set, _ := NewInstructionSet(Instruction{
+			Name:  "sum3",
+			Arity: 3,
+			Arg:   ArgGrammar{MinDigits: 1, MaxDigits: 2, Signed: true},
+			Eval:  func(args []int) int { return args[0] + args[1] + args[2] },
+		})
+
+		part1, _, _ := SolveWith(strings.NewReader("sum3(1,-2,10)sum3(1,2)sum3(100,1,1)sum3(-,1,1)"), set)
+
+		utils.CheckEqual(part1, 9, t)
+	})
+
+	t.Run("nested toggles", func(t *testing.T) {
+		set, _ := NewInstructionSet(
+			MulInstruction,
+			Instruction{Name: "push", Toggle: func(depth int) int { return depth + 1 }},
+			Instruction{Name: "pop", Toggle: func(depth int) int { return max(0, depth-1) }},
+		)
+
+		input := "mul(1,1)push()mul(2,2)push()mul(3,3)pop()mul(4,4)pop()mul(5,5)"
+		got := scan(input, set)
+
+		enabled := utils.Map(utils.Filter(got, func(s scanned) bool { return s.name == "mul" }),
+			func(s scanned) bool { return s.enabled })
+
+		utils.CheckEqual(enabled, []bool{true, false, false, false, true}, t)
+	})
+}
@@ -4,9 +4,8 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -25,34 +24,67 @@ func GetInput(filename string) (*Input, error) {
 		lines = append(lines, line)
 	}
 
-	fullMemory := strings.Join(lines, "")
+	// keep line breaks; an instruction split across lines is not an instruction
+	fullMemory := strings.Join(lines, "\n")
 
 	return &Input{memory: fullMemory}, nil
 }
 
 func main() {
-	input, _ := GetInput("input.txt")
-	Part1(input.memory)
-	Part2(input.memory)
+	file, err := os.Open("input.txt")
+	if err != nil {
+		fmt.Println(ErrInputFile)
+		return
+	}
+	defer file.Close()
+
+	p1Result, p2Result, err := Solve(file)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Printf("Part 1: got <%v>\n", p1Result)
+	fmt.Printf("Part 2: got <%v>\n", p2Result)
 }
 
 func Part1(input string) int {
-	matches := GetMatches(input)
-	result := EvaluateMatches(matches)
+	tokens := NewTokenizer(strings.NewReader(input), DefaultInstructionSet())
+	result := Fold(tokens.Tokens(), 0, SumValues)
 
 	fmt.Printf("Part 1: got <%v>\n", result)
 	return result
 }
 
 func Part2(input string) int {
-	cleanInput := RemoveAfterDontUntilDoOrEnd(input)
-	matches := GetMatches(cleanInput)
-	result := EvaluateMatches(matches)
+	tokens := NewTokenizer(strings.NewReader(input), DefaultInstructionSet())
+	result := Fold(tokens.Tokens(), 0, SumEnabledValues)
 
 	fmt.Printf("Part 2: got <%v>\n", result)
 	return result
 }
 
+// both parts in a single pass over r, without holding the memory in full
+func Solve(r io.Reader) (int, int, error) {
+	return SolveWith(r, DefaultInstructionSet())
+}
+
+func SolveWith(r io.Reader, instructions *InstructionSet) (int, int, error) {
+	type sums struct {
+		all, enabled int
+	}
+
+	tokens := NewTokenizer(r, instructions)
+	result := Fold(tokens.Tokens(), sums{}, func(s sums, t Token) sums {
+		return sums{
+			all:     SumValues(s.all, t),
+			enabled: SumEnabledValues(s.enabled, t),
+		}
+	})
+
+	return result.all, result.enabled, tokens.Err()
+}
+
 type Match struct {
 	left, right int
 }
@@ -67,70 +99,35 @@ func EvaluateMatches(ms []Match) int {
 }
 
 func GetMatches(input string) []Match {
-	r, _ := regexp.Compile(`mul\(([0-9]+),([0-9]+)\)`)
 	var matches []Match
-	results := r.FindAllStringSubmatch(input, -1)
-	if results == nil {
-		return matches
+	instructions, _ := NewInstructionSet(MulInstruction)
+	tokens := NewTokenizer(strings.NewReader(input), instructions)
+	for t := range tokens.Tokens() {
+		matches = append(matches, Match{left: t.Args[0], right: t.Args[1]})
 	}
-
-	for _, result := range results {
-		left, _ := strconv.Atoi(result[1])
-		right, _ := strconv.Atoi(result[2])
-		match := Match{
-			left:  left,
-			right: right,
-		}
-		matches = append(matches, match)
-	}
-
 	return matches
 }
 
 // remove `dont().*` up until do(), returned as new string
 func RemoveAfterDontUntilDoOrEnd(input string) string {
-	pattern := `(don't\(\)).+?(do\(\))`
-	r, _ := regexp.Compile(pattern)
-
-	outcomeSlice := strings.Split(input, "")
-	var outcomeString string
-	inputBytes := []byte(input)
-
-	results := r.FindSubmatchIndex(inputBytes)
-
-	if results == nil {
-		return input
-	}
-	for results != nil {
-		dontStartIndex := 2
-		doEndIndex := 5
-
-		newSlice := make([]string, 0)
-
-		newSlice = append(newSlice, outcomeSlice[:results[dontStartIndex]]...)
-		newSlice = append(newSlice, outcomeSlice[results[doEndIndex]:]...)
-
-		outcomeSlice = newSlice
-		outcomeString = strings.Join(outcomeSlice, "")
-		results = r.FindSubmatchIndex([]byte(outcomeString))
-	}
-
-	// check for outstanding `don't().*`
-	lastR, _ := regexp.Compile(`(don't\(\).*)`)
-	results = lastR.FindSubmatchIndex([]byte(outcomeString))
+	var sb strings.Builder
+	rest := input
+
+	for {
+		dont := strings.Index(rest, "don't()")
+		if dont == -1 {
+			sb.WriteString(rest)
+			return sb.String()
+		}
+		sb.WriteString(rest[:dont])
+		rest = rest[dont+len("don't()"):]
 
-	if results == nil {
-		return outcomeString
+		do := strings.Index(rest, "do()")
+		if do == -1 {
+			return sb.String()
+		}
+		rest = rest[do+len("do()"):]
 	}
-
-	dontStartIndex := 2
-	newSlice := make([]string, 0)
-	newSlice = append(newSlice, outcomeSlice[:results[dontStartIndex]]...)
-
-	outcomeSlice = newSlice
-	outcomeString = strings.Join(outcomeSlice, "")
-
-	return outcomeString
 }
 
 type Input struct {
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+)
+
+// a recognised instruction, with the byte offset of its first character and
+// whether instructions were enabled when it was read
+type Token struct {
+	Instruction *Instruction
+	Args        []int
+	Offset      int64
+	Enabled     bool
+}
+
+func (t Token) Name() string {
+	return t.Instruction.Name
+}
+
+// the instruction's value, regardless of whether it was enabled
+func (t Token) Value() int {
+	if t.Instruction.Eval == nil {
+		return 0
+	}
+	return t.Instruction.Eval(t.Args)
+}
+
+// an instruction part-way through being matched
+type candidate struct {
+	instruction *Instruction
+	offset      int64
+	// bytes of `name(` matched so far
+	matched  int
+	args     []int
+	value    int
+	digits   int
+	negative bool
+}
+
+// feed one byte to the candidate, returning whether it's still alive and whether it's complete
+func (c *candidate) step(b byte) (bool, bool) {
+	literal := c.instruction.literal()
+	if c.matched < len(literal) {
+		if literal[c.matched] != b {
+			return false, false
+		}
+		c.matched++
+		return true, false
+	}
+
+	if c.instruction.Arity == 0 {
+		return b == ')', b == ')'
+	}
+
+	grammar := c.instruction.Arg
+	last := len(c.args) == c.instruction.Arity-1
+
+	switch {
+	case b == '-' && grammar.Signed && c.digits == 0 && !c.negative:
+		c.negative = true
+	case isDigit(b) && c.digits < grammar.MaxDigits:
+		c.value = c.value*10 + int(b-'0')
+		c.digits++
+	case c.digits >= grammar.MinDigits && ((b == ',' && !last) || (b == ')' && last)):
+		if c.negative {
+			c.value = -c.value
+		}
+		c.args = append(c.args, c.value)
+		c.value, c.digits, c.negative = 0, 0, false
+		return true, b == ')'
+	default:
+		return false, false
+	}
+
+	return true, false
+}
+
+// Tokenizer reads corrupted memory a byte at a time, emitting the instructions in its
+// InstructionSet. Memory is never buffered beyond the bufio.Reader, so input can be any size.
+//
+// Every instruction whose name starts at a byte is tried in parallel. When one completes
+// it's emitted and all other partial matches are dropped, so tokens never overlap; if
+// several complete on the same byte, the one that started first wins.
+type Tokenizer struct {
+	r            *bufio.Reader
+	instructions *InstructionSet
+	err          error
+}
+
+func NewTokenizer(r io.Reader, instructions *InstructionSet) *Tokenizer {
+	return &Tokenizer{r: bufio.NewReader(r), instructions: instructions}
+}
+
+// the first non-EOF read error hit by Tokens, if any
+func (t *Tokenizer) Err() error {
+	return t.err
+}
+
+// Tokens is a single-use iterator over the instructions in the reader.
+func (t *Tokenizer) Tokens() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		var active, next []*candidate
+		var offset int64 = -1
+		depth := 0
+
+		for {
+			b, err := t.r.ReadByte()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					t.err = err
+				}
+				return
+			}
+			offset++
+
+			for _, i := range t.instructions.instructions {
+				if i.Name[0] == b {
+					active = append(active, &candidate{instruction: i, offset: offset})
+				}
+			}
+
+			var done *candidate
+			next = next[:0]
+			for _, c := range active {
+				alive, complete := c.step(b)
+				if complete && done == nil {
+					done = c
+				}
+				if alive && !complete {
+					next = append(next, c)
+				}
+			}
+			active, next = next, active
+
+			if done == nil {
+				continue
+			}
+			active = active[:0]
+
+			token := Token{
+				Instruction: done.instruction,
+				Args:        done.args,
+				Offset:      done.offset,
+				Enabled:     depth == 0,
+			}
+			if done.instruction.Toggle != nil {
+				depth = done.instruction.Toggle(depth)
+			}
+
+			if !yield(token) {
+				return
+			}
+		}
+	}
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func Fold[A any](tokens iter.Seq[Token], initial A, fn func(A, Token) A) A {
+	acc := initial
+	for t := range tokens {
+		acc = fn(acc, t)
+	}
+	return acc
+}
+
+// Part 1 reducer: every instruction counts
+func SumValues(sum int, t Token) int {
+	return sum + t.Value()
+}
+
+// Part 2 reducer: only enabled instructions count
+func SumEnabledValues(sum int, t Token) int {
+	if !t.Enabled {
+		return sum
+	}
+	return sum + t.Value()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+type scanned struct {
+	name    string
+	args    []int
+	offset  int64
+	enabled bool
+}
+
+func scan(input string, instructions *InstructionSet) []scanned {
+	tokens := NewTokenizer(strings.NewReader(input), instructions)
+	return utils.Map(utils.IterSeqToSlice(tokens.Tokens()), func(t Token) scanned {
+		return scanned{t.Name(), t.Args, t.Offset, t.Enabled}
+	})
+}
+
+func TestTokenizer(t *testing.T) {
+	t.Run("emits instructions with offsets and enabled state", func(t *testing.T) {
+		input := "xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))"
+		want := []scanned{
+			{"mul", []int{2, 4}, 1, true},
+			{"don't", nil, 20, true},
+			{"mul", []int{5, 5}, 28, false},
+			{"mul", []int{11, 8}, 48, false},
+			{"do", nil, 59, false},
+			{"mul", []int{8, 5}, 64, true},
+		}
+
+		got := scan(input, DefaultInstructionSet())
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("arguments are 1-3 digits", func(t *testing.T) {
+		input := "mul(1234,5)mul(,5)mul(123,456)mul(4,)"
+		want := []scanned{
+			{"mul", []int{123, 456}, 18, true},
+		}
+
+		got := scan(input, DefaultInstructionSet())
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("restarts on the byte that broke a partial match", func(t *testing.T) {
+		input := "mmul(1,2)mul(3,mul(4,5)dodon't()do(do()"
+		want := []string{"mul", "mul", "don't", "do"}
+
+		got := utils.Map(scan(input, DefaultInstructionSet()), func(s scanned) string { return s.name })
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("instructions do not span newlines", func(t *testing.T) {
+		input := "mul(1,\n2)mul(3,4)"
+		want := []scanned{
+			{"mul", []int{3, 4}, 9, true},
+		}
+
+		got := scan(input, DefaultInstructionSet())
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("overlapping names prefer the earliest start", func(t *testing.T) {
+		instructions, _ := NewInstructionSet(DoInstruction, Instruction{Name: "undo"})
+		input := "undo()do()"
+		want := []scanned{
+			{"undo", nil, 0, true},
+			{"do", nil, 6, true},
+		}
+
+		got := scan(input, instructions)
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("works one byte at a time", func(t *testing.T) {
+		input := iotest.OneByteReader(strings.NewReader("don't()mul(2,3)do()mul(4,5)"))
+		part1, part2, err := Solve(input)
+
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(part1, 26, t)
+		utils.CheckEqual(part2, 20, t)
+	})
+
+	t.Run("surfaces read errors", func(t *testing.T) {
+		boom := errors.New("boom")
+		input := iotest.DataErrReader(iotest.ErrReader(boom))
+		_, _, err := Solve(input)
+
+		utils.CheckEqual(err, boom, t)
+	})
+}
+
+func TestSolve(t *testing.T) {
+	input := "xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))"
+	part1, part2, err := Solve(strings.NewReader(input))
+
+	utils.CheckEqual(err, nil, t)
+	utils.CheckEqual(part1, 161, t)
+	utils.CheckEqual(part2, 48, t)
+}
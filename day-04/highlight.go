@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// anything with letters at points in the grid, i.e. a Match or StencilMatch
+type Located interface {
+	Points() []Point
+}
+
+// a stencil fitting the grid with its top-left corner at At
+type StencilMatch struct {
+	Stencil Stencil
+	At      Point
+}
+
+// the points the stencil's letters cover; wildcards aren't included
+func (m StencilMatch) Points() []Point {
+	points := make([]Point, 0, len(m.Stencil.cells))
+	for p := range m.Stencil.cells {
+		points = append(points, Point{m.At.x + p.x, m.At.y + p.y})
+	}
+	return points
+}
+
+func (input *Input) StencilMatches(stencils ...Stencil) []StencilMatch {
+	var matches []StencilMatch
+	for _, s := range stencils {
+		for _, at := range input.FindStencil(s) {
+			matches = append(matches, StencilMatch{s, at})
+		}
+	}
+	return matches
+}
+
+// Highlight draws the grid as the puzzle's examples do, with letters that aren't part of
+// any match replaced by '.'.
+func Highlight[M Located](input *Input, matches []M) string {
+	return render(input, matches, func(letter string, _ int) string { return letter }, ".")
+}
+
+// colours cycled through for successive matches
+var highlightColours = []int{31, 32, 33, 34, 35, 36}
+
+// HighlightANSI draws the whole grid for a terminal: letters in a match are bold and
+// coloured, a colour per match (a letter in several matches takes the first's), and the
+// rest are dimmed.
+func HighlightANSI[M Located](input *Input, matches []M) string {
+	colour := func(letter string, match int) string {
+		return fmt.Sprintf("\x1b[1;%vm%v\x1b[0m", highlightColours[match%len(highlightColours)], letter)
+	}
+	return render(input, matches, colour, "")
+}
+
+// render draws each cell with draw if it's in a match (given the index of the first match
+// covering it) and otherwise as unmatched, or dimmed if unmatched is empty
+func render[M Located](input *Input, matches []M, draw func(letter string, match int) string, unmatched string) string {
+	firstMatch := make(map[Point]int)
+	for i, m := range matches {
+		for _, p := range m.Points() {
+			if _, ok := firstMatch[p]; !ok {
+				firstMatch[p] = i
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for y := 0; y <= input.maxY; y++ {
+		for x := 0; x <= input.maxX; x++ {
+			p := Point{x, y}
+			letter := input.grid[p]
+			switch i, ok := firstMatch[p]; {
+			case ok:
+				sb.WriteString(draw(letter, i))
+			case unmatched != "":
+				sb.WriteString(unmatched)
+			default:
+				sb.WriteString("\x1b[2m" + letter + "\x1b[0m")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}{p.x, p.y})
+}
+
+func (d Direction) MarshalText() ([]byte, error) {
+	if _, ok := directionNames[d]; !ok {
+		return nil, fmt.Errorf("no such direction %v,%v", d.dx, d.dy)
+	}
+	return []byte(d.String()), nil
+}
+
+func (d *Direction) UnmarshalText(text []byte) error {
+	i := slices.IndexFunc(Directions, func(o Direction) bool { return o.String() == string(text) })
+	if i == -1 {
+		return fmt.Errorf("no such direction %q", text)
+	}
+	*d = Directions[i]
+	return nil
+}
+
+func (m Match) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Word      string    `json:"word"`
+		Start     Point     `json:"start"`
+		Direction Direction `json:"direction"`
+	}{m.Word, m.Start, m.Direction})
+}
+
+// MatchesJSON lists the matches as e.g.
+// [{"word":"XMAS","start":{"x":4,"y":0},"direction":"down-right"}]
+func MatchesJSON(matches []Match) ([]byte, error) {
+	if matches == nil {
+		matches = []Match{}
+	}
+	return json.Marshal(matches)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestHighlight(t *testing.T) {
+	input := ReadInput(strings.NewReader(example))
+
+	t.Run("words", func(t *testing.T) {
+		want := `....XXMAS.
+.SAMXMS...
+...S..A...
+..A.A.MS.X
+XMASAMX.MM
+X.....XA.A
+S.S.S.S.SS
+.A.A.A.A.A
+..M.M.M.MM
+.X.X.XMASX
+`
+		utils.CheckEqual(Highlight(input, input.FindWords("XMAS")), want, t)
+	})
+
+	t.Run("stencils", func(t *testing.T) {
+		want := `.M.S......
+..A..MSMS.
+.M.S.MAA..
+..A.ASMSM.
+.M.S.M....
+..........
+S.S.S.S.S.
+.A.A.A.A..
+M.M.M.M.M.
+..........
+`
+		utils.CheckEqual(Highlight(input, input.StencilMatches(XMasCross.Rotations()...)), want, t)
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		small := ReadInput(strings.NewReader("AB\nCD"))
+		utils.CheckEqual(Highlight(small, []Match{}), "..\n..\n", t)
+	})
+
+	t.Run("ANSI", func(t *testing.T) {
+		small := ReadInput(strings.NewReader("XMAS\nXMAS\nABCD"))
+		got := HighlightANSI(small, small.FindWords("XMAS", "AB"))
+		lines := strings.Split(got, "\n")
+
+		utils.CheckEqual(lines[0], "\x1b[1;31mX\x1b[0m\x1b[1;31mM\x1b[0m\x1b[1;31mA\x1b[0m\x1b[1;31mS\x1b[0m", t)
+		utils.CheckEqual(strings.HasPrefix(lines[1], "\x1b[1;32mX"), true, t)
+		utils.CheckEqual(lines[2], "\x1b[1;34mA\x1b[0m\x1b[1;33mB\x1b[0m\x1b[2mC\x1b[0m\x1b[2mD\x1b[0m", t)
+	})
+}
+
+func TestMatchesJSON(t *testing.T) {
+	out, err := MatchesJSON([]Match{{"XMAS", Point{4, 0}, DOWN_RIGHT}, {"XMAS", Point{9, 9}, UP_LEFT}})
+	utils.CheckEqual(err, nil, t)
+	utils.CheckEqual(string(out), `[{"word":"XMAS","start":{"x":4,"y":0},"direction":"down-right"},{"word":"XMAS","start":{"x":9,"y":9},"direction":"up-left"}]`, t)
+
+	empty, _ := MatchesJSON(nil)
+	utils.CheckEqual(string(empty), "[]", t)
+
+	t.Run("directions round trip", func(t *testing.T) {
+		var decoded []Direction
+		encoded, _ := json.Marshal(Directions)
+		utils.CheckEqual(json.Unmarshal(encoded, &decoded), nil, t)
+		utils.CheckEqual(decoded, Directions, t)
+
+		var d Direction
+		utils.CheckEqual(d.UnmarshalText([]byte("sideways")) != nil, true, t)
+	})
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -29,7 +31,11 @@ func GetInput(filename string) (*Input, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return ReadInput(file), nil
+}
+
+func ReadInput(r io.Reader) *Input {
+	scanner := bufio.NewScanner(r)
 
 	var maxX int
 	var y = 0
@@ -46,11 +52,37 @@ func GetInput(filename string) (*Input, error) {
 	}
 
 	maxY := y - 1
-	return &Input{maxX: maxX, maxY: maxY, grid: grid}, nil
+	return &Input{maxX: maxX, maxY: maxY, grid: grid}
 }
 
 func main() {
+	highlight := flag.Bool("highlight", false, "print the grid showing only the letters of each match")
+	colour := flag.Bool("colour", false, "with -highlight, colour the matches instead of hiding other letters")
+	asJSON := flag.Bool("json", false, "print the Part 1 matches as JSON")
+	flag.Parse()
+
 	input, _ := GetInput("input.txt")
+
+	if *asJSON {
+		out, err := MatchesJSON(input.FindWords("XMAS"))
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(string(out))
+		return
+	}
+
+	if *highlight {
+		words := input.FindWords("XMAS")
+		crosses := input.StencilMatches(XMasCross.Rotations()...)
+		if *colour {
+			fmt.Print(HighlightANSI(input, words), "\n", HighlightANSI(input, crosses))
+		} else {
+			fmt.Print(Highlight(input, words), "\n", Highlight(input, crosses))
+		}
+		return
+	}
 	p1Result := Part1(input)
 	fmt.Printf("Part 1: got %v\n", p1Result)
 
@@ -59,53 +91,11 @@ func main() {
 }
 
 func Part1(input *Input) int {
-	// TODO
-	// 1. get starting points of X
-	// 2. get combinations of 4 letters in row (up,down,left,right,diagonals) from starting point
-	// 3. count those that spell "XMAS"
-	// 4. donezo.
-
-	startingPoints := GetCoordsOfLetter(input.grid, "X")
-	counter := 0
-
-	for _, p := range startingPoints {
-		sequencesToConsider := GetSeqOf4PointsSurrounding(p, input.maxX, input.maxY)
-
-		for _, ps := range sequencesToConsider {
-			if GetPointsAsString(input.grid, ps...) == "XMAS" {
-				counter++
-			}
-		}
-	}
-
-	return counter
+	return len(input.FindWords("XMAS"))
 }
 
 func Part2(input *Input) int {
-	// TODO
-	// 1. get start point of X
-	// 2. check if diagonals spell MAS, increment counter when 2 diagonals spell MAS (e.g. a X)
-	// 3. donezo?
-
-	letterAs := GetCoordsOfLetter(input.grid, "A")
-	counter := 0
-
-	for _, p := range letterAs {
-		toConsider := GetSeqOfDiagonalsOf3CharLong(p, input.maxX, input.maxY)
-
-		diagMatches := 0
-		for _, ps := range toConsider {
-			if GetPointsAsString(input.grid, ps...) == "MAS" {
-				diagMatches++
-			}
-		}
-		// 2+ diagonal matches makes it a X-MAS
-		if diagMatches >= 2 {
-			counter++
-		}
-	}
-
-	return counter
+	return input.CountStencils(XMasCross.Rotations()...)
 }
 
 func GetPointsAsString(grid GridMap, ps ...Point) string {
@@ -118,101 +108,6 @@ func GetPointsAsString(grid GridMap, ps ...Point) string {
 	return strings.Join(result, "")
 }
 
-func GetSeqOfDiagonalsOf3CharLong(p Point, maxX, maxY int) [][]Point {
-	var results [][]Point
-	spaceUL := p.x >= 1 && p.y >= 1
-	spaceDL := p.x >= 1 && p.y+1 <= maxY
-	spaceUR := p.x+1 <= maxX && p.y >= 1
-	spaceDR := p.x+1 <= maxX && p.y+1 <= maxY
-
-	if spaceUL && spaceDR {
-		ulDiag := []Point{
-			{p.x - 1, p.y - 1},
-			p,
-			{p.x + 1, p.y + 1},
-		}
-		results = append(results, ulDiag)
-
-		drDiag := []Point{
-			{p.x + 1, p.y + 1},
-			p,
-			{p.x - 1, p.y - 1},
-		}
-		results = append(results, drDiag)
-	}
-
-	if spaceUR && spaceDL {
-		urDiag := []Point{
-			{p.x + 1, p.y - 1},
-			p,
-			{p.x - 1, p.y + 1},
-		}
-		results = append(results, urDiag)
-
-		dlDiag := []Point{
-			{p.x - 1, p.y + 1},
-			p,
-			{p.x + 1, p.y - 1},
-		}
-		results = append(results, dlDiag)
-	}
-	return results
-}
-
-func GetSeqOf4PointsSurrounding(p Point, maxX, maxY int) [][]Point {
-	var results [][]Point
-	spaceLeft := p.x >= 3
-	spaceRight := p.x+3 <= maxX
-	spaceUp := p.y >= 3
-	spaceDown := p.y+3 <= maxY
-	spaceUL := spaceUp && spaceLeft
-	spaceDL := spaceDown && spaceLeft
-	spaceUR := spaceUp && spaceRight
-	spaceDR := spaceDown && spaceRight
-
-	if spaceLeft {
-		seq := []Point{p, Point{p.x - 1, p.y}, Point{p.x - 2, p.y}, Point{p.x - 3, p.y}}
-		results = append(results, seq)
-	}
-
-	if spaceRight {
-		seq := []Point{p, Point{p.x + 1, p.y}, Point{p.x + 2, p.y}, Point{p.x + 3, p.y}}
-		results = append(results, seq)
-	}
-
-	if spaceUp {
-		seq := []Point{p, Point{p.x, p.y - 1}, Point{p.x, p.y - 2}, Point{p.x, p.y - 3}}
-		results = append(results, seq)
-	}
-
-	if spaceDown {
-		seq := []Point{p, Point{p.x, p.y + 1}, Point{p.x, p.y + 2}, Point{p.x, p.y + 3}}
-		results = append(results, seq)
-	}
-
-	if spaceUL {
-		seq := []Point{p, Point{p.x - 1, p.y - 1}, Point{p.x - 2, p.y - 2}, Point{p.x - 3, p.y - 3}}
-		results = append(results, seq)
-	}
-
-	if spaceDL {
-		seq := []Point{p, Point{p.x - 1, p.y + 1}, Point{p.x - 2, p.y + 2}, Point{p.x - 3, p.y + 3}}
-		results = append(results, seq)
-	}
-
-	if spaceUR {
-		seq := []Point{p, Point{p.x + 1, p.y - 1}, Point{p.x + 2, p.y - 2}, Point{p.x + 3, p.y - 3}}
-		results = append(results, seq)
-	}
-
-	if spaceDR {
-		seq := []Point{p, Point{p.x + 1, p.y + 1}, Point{p.x + 2, p.y + 2}, Point{p.x + 3, p.y + 3}}
-		results = append(results, seq)
-	}
-
-	return results
-}
-
 func GetCoordsOfLetter(grid GridMap, letter string) []Point {
 	var result []Point
 
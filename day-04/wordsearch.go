@@ -0,0 +1,304 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// a step between neighbouring cells
+type Direction struct {
+	dx, dy int
+}
+
+var (
+	RIGHT      = Direction{1, 0}
+	DOWN_RIGHT = Direction{1, 1}
+	DOWN       = Direction{0, 1}
+	DOWN_LEFT  = Direction{-1, 1}
+	LEFT       = Direction{-1, 0}
+	UP_LEFT    = Direction{-1, -1}
+	UP         = Direction{0, -1}
+	UP_RIGHT   = Direction{1, -1}
+)
+
+// all eight, clockwise from RIGHT
+var Directions = []Direction{RIGHT, DOWN_RIGHT, DOWN, DOWN_LEFT, LEFT, UP_LEFT, UP, UP_RIGHT}
+
+var directionNames = map[Direction]string{
+	RIGHT: "right", DOWN_RIGHT: "down-right", DOWN: "down", DOWN_LEFT: "down-left",
+	LEFT: "left", UP_LEFT: "up-left", UP: "up", UP_RIGHT: "up-right",
+}
+
+func (d Direction) String() string {
+	return directionNames[d]
+}
+
+func (p Point) Step(d Direction, n int) Point {
+	return Point{p.x + d.dx*n, p.y + d.dy*n}
+}
+
+// a word found in the grid, reading from Start in Direction
+type Match struct {
+	Word      string
+	Start     Point
+	Direction Direction
+}
+
+func (m Match) Points() []Point {
+	points := make([]Point, utf8.RuneCountInString(m.Word))
+	for i := range points {
+		points[i] = m.Start.Step(m.Direction, i)
+	}
+	return points
+}
+
+// Automaton is an Aho–Corasick matcher: a trie of the words with failure links, so one
+// pass over a line finds every occurrence of every word.
+type Automaton struct {
+	next []map[rune]int
+	fail []int
+	// words ending at each state, including via failure links
+	output [][]string
+}
+
+func NewAutomaton(words ...string) *Automaton {
+	a := &Automaton{}
+	a.addState()
+
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		state := 0
+		for _, r := range w {
+			n, ok := a.next[state][r]
+			if !ok {
+				n = a.addState()
+				a.next[state][r] = n
+			}
+			state = n
+		}
+		if !slices.Contains(a.output[state], w) {
+			a.output[state] = append(a.output[state], w)
+		}
+	}
+
+	// breadth first, so a state's failure link is always set before its children's
+	queue := []int{}
+	for _, n := range a.next[0] {
+		queue = append(queue, n)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for r, n := range a.next[state] {
+			f := a.fail[state]
+			for f != 0 && a.next[f][r] == 0 {
+				f = a.fail[f]
+			}
+			if target, ok := a.next[f][r]; ok && target != n {
+				a.fail[n] = target
+			}
+			a.output[n] = append(a.output[n], a.output[a.fail[n]]...)
+			queue = append(queue, n)
+		}
+	}
+
+	return a
+}
+
+func (a *Automaton) addState() int {
+	a.next = append(a.next, make(map[rune]int))
+	a.fail = append(a.fail, 0)
+	a.output = append(a.output, nil)
+	return len(a.next) - 1
+}
+
+// Scan feeds a line through the automaton, calling found with each word and the index of
+// its last rune.
+func (a *Automaton) Scan(line []rune, found func(word string, end int)) {
+	state := 0
+	for i, r := range line {
+		for state != 0 && a.next[state][r] == 0 {
+			state = a.fail[state]
+		}
+		state = a.next[state][r]
+		for _, w := range a.output[state] {
+			found(w, i)
+		}
+	}
+}
+
+func (input *Input) At(p Point) rune {
+	r, _ := utf8.DecodeRuneInString(input.grid[p])
+	return r
+}
+
+func (input *Input) InBounds(p Point) bool {
+	return p.x >= 0 && p.x <= input.maxX && p.y >= 0 && p.y <= input.maxY
+}
+
+// lineStarts gives the first cell of every line through the grid in direction d, i.e.
+// each cell whose predecessor along d is off the grid
+func (input *Input) lineStarts(d Direction) []Point {
+	var starts []Point
+	for y := 0; y <= input.maxY; y++ {
+		for x := 0; x <= input.maxX; x++ {
+			p := Point{x, y}
+			if !input.InBounds(p.Step(d, -1)) {
+				starts = append(starts, p)
+			}
+		}
+	}
+	return starts
+}
+
+// FindWords finds every occurrence of the words in any of the eight directions. Each line
+// through the grid is scanned once per direction, so the cost doesn't grow with the
+// number of words. A word that reads the same backwards is found once each way.
+func (input *Input) FindWords(words ...string) []Match {
+	automaton := NewAutomaton(words...)
+	var matches []Match
+
+	for _, d := range Directions {
+		for _, start := range input.lineStarts(d) {
+			var line []rune
+			for p := start; input.InBounds(p); p = p.Step(d, 1) {
+				line = append(line, input.At(p))
+			}
+
+			automaton.Scan(line, func(word string, end int) {
+				first := end - utf8.RuneCountInString(word) + 1
+				matches = append(matches, Match{Word: word, Start: start.Step(d, first), Direction: d})
+			})
+		}
+	}
+
+	slices.SortFunc(matches, compareMatches)
+	return matches
+}
+
+// reading order of start, then clockwise direction, then word
+func compareMatches(a, b Match) int {
+	return cmp.Or(
+		cmp.Compare(a.Start.y, b.Start.y),
+		cmp.Compare(a.Start.x, b.Start.x),
+		cmp.Compare(slices.Index(Directions, a.Direction), slices.Index(Directions, b.Direction)),
+		cmp.Compare(a.Word, b.Word),
+	)
+}
+
+// any letter matches a wildcard in a stencil
+const WILDCARD = '.'
+
+// Stencil is a small 2D pattern of letters, with wildcards where anything goes.
+type Stencil struct {
+	cells  map[Point]rune
+	width  int
+	height int
+}
+
+// ParseStencil reads a stencil from rows of text, e.g. the X-MAS cross is
+// ParseStencil("M.S", ".A.", "M.S").
+func ParseStencil(rows ...string) Stencil {
+	s := Stencil{cells: make(map[Point]rune), height: len(rows)}
+	for y, row := range rows {
+		x := 0
+		for _, r := range row {
+			if r != WILDCARD {
+				s.cells[Point{x, y}] = r
+			}
+			x++
+		}
+		s.width = max(s.width, x)
+	}
+	return s
+}
+
+var (
+	XMasCross = ParseStencil("M.S", ".A.", "M.S")
+	MasPlus   = ParseStencil(".M.", "MAS", ".S.")
+)
+
+// the stencil turned a quarter clockwise
+func (s Stencil) Rotate() Stencil {
+	rotated := Stencil{cells: make(map[Point]rune, len(s.cells)), width: s.height, height: s.width}
+	for p, r := range s.cells {
+		rotated.cells[Point{s.height - 1 - p.y, p.x}] = r
+	}
+	return rotated
+}
+
+// the distinct quarter-turns of the stencil, starting with itself
+func (s Stencil) Rotations() []Stencil {
+	rotations := []Stencil{s}
+	for current := s.Rotate(); len(rotations) < 4; current = current.Rotate() {
+		if slices.ContainsFunc(rotations, current.Equal) {
+			break
+		}
+		rotations = append(rotations, current)
+	}
+	return rotations
+}
+
+func (s Stencil) Equal(o Stencil) bool {
+	if s.width != o.width || s.height != o.height || len(s.cells) != len(o.cells) {
+		return false
+	}
+	for p, r := range s.cells {
+		if o.cells[p] != r {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Stencil) String() string {
+	rows := make([]string, s.height)
+	for y := range s.height {
+		var sb strings.Builder
+		for x := range s.width {
+			if r, ok := s.cells[Point{x, y}]; ok {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune(WILDCARD)
+			}
+		}
+		rows[y] = sb.String()
+	}
+	return strings.Join(rows, "\n")
+}
+
+// FindStencil returns the top-left corner of every place the stencil fits the grid.
+func (input *Input) FindStencil(s Stencil) []Point {
+	var found []Point
+	for y := 0; y+s.height-1 <= input.maxY; y++ {
+		for x := 0; x+s.width-1 <= input.maxX; x++ {
+			if input.stencilFits(s, Point{x, y}) {
+				found = append(found, Point{x, y})
+			}
+		}
+	}
+	return found
+}
+
+func (input *Input) stencilFits(s Stencil, at Point) bool {
+	for p, r := range s.cells {
+		if input.At(Point{at.x + p.x, at.y + p.y}) != r {
+			return false
+		}
+	}
+	return true
+}
+
+// CountStencils counts every fit of every stencil, e.g. of XMasCross.Rotations().
+func (input *Input) CountStencils(stencils ...Stencil) int {
+	count := 0
+	for _, s := range stencils {
+		count += len(input.FindStencil(s))
+	}
+	return count
+}
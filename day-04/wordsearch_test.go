@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+const example = `MMMSXXMASM
+MSAMXMSMSA
+AMXSXMAAMM
+MSAMASMSMX
+XMASAMXAMM
+XXAMMXXAMA
+SMSMSASXSS
+SAXAMASAAA
+MAMMMXMMMM
+MXMXAXMASX`
+
+func TestAutomaton(t *testing.T) {
+	type found struct {
+		word string
+		end  int
+	}
+
+	var got []found
+	NewAutomaton("he", "she", "his", "hers").Scan([]rune("ushers"), func(word string, end int) {
+		got = append(got, found{word, end})
+	})
+
+	utils.CheckSlicesHaveSameElements(got, []found{{"she", 3}, {"he", 3}, {"hers", 5}}, t)
+}
+
+func TestFindWords(t *testing.T) {
+	t.Run("puzzle example", func(t *testing.T) {
+		input := ReadInput(strings.NewReader(example))
+		matches := input.FindWords("XMAS")
+
+		utils.CheckEqual(len(matches), 18, t)
+		for _, m := range matches {
+			utils.CheckEqual(GetPointsAsString(input.grid, m.Points()...), "XMAS", t)
+		}
+		utils.CheckEqual(matches[0], Match{"XMAS", Point{4, 0}, DOWN_RIGHT}, t)
+	})
+
+	t.Run("reports direction", func(t *testing.T) {
+		input := ReadInput(strings.NewReader("X..X\n.M.M\n..AA\nXMAS"))
+		utils.CheckEqual(input.FindWords("XMAS"), []Match{
+			{"XMAS", Point{0, 0}, DOWN_RIGHT},
+			{"XMAS", Point{3, 0}, DOWN},
+			{"XMAS", Point{0, 3}, RIGHT},
+		}, t)
+	})
+
+	t.Run("several words sharing suffixes", func(t *testing.T) {
+		input := ReadInput(strings.NewReader("ABCD"))
+		utils.CheckEqual(input.FindWords("BCD", "CD", "DC"), []Match{
+			{"BCD", Point{1, 0}, RIGHT},
+			{"CD", Point{2, 0}, RIGHT},
+			{"DC", Point{3, 0}, LEFT},
+		}, t)
+	})
+
+	t.Run("palindromes are found both ways", func(t *testing.T) {
+		input := ReadInput(strings.NewReader("ABA"))
+		utils.CheckEqual(input.FindWords("ABA"), []Match{
+			{"ABA", Point{0, 0}, RIGHT},
+			{"ABA", Point{2, 0}, LEFT},
+		}, t)
+	})
+
+	t.Run("no words", func(t *testing.T) {
+		input := ReadInput(strings.NewReader(example))
+		utils.CheckEqual(len(input.FindWords()), 0, t)
+		utils.CheckEqual(len(input.FindWords("")), 0, t)
+	})
+}
+
+func TestStencil(t *testing.T) {
+	t.Run("rotations", func(t *testing.T) {
+		utils.CheckEqual(XMasCross.Rotate().String(), "M.M\n.A.\nS.S", t)
+		utils.CheckEqual(len(XMasCross.Rotations()), 4, t)
+		utils.CheckEqual(len(MasPlus.Rotations()), 4, t)
+		utils.CheckEqual(len(ParseStencil("A.A", ".A.", "A.A").Rotations()), 1, t)
+		utils.CheckEqual(len(ParseStencil("AB", "BA").Rotations()), 2, t)
+	})
+
+	t.Run("non-square", func(t *testing.T) {
+		s := ParseStencil("XM", ".A")
+		utils.CheckEqual(ParseStencil("XMAS").Rotate().String(), "X\nM\nA\nS", t)
+		utils.CheckEqual(s.Rotate().Rotate().Rotate().Rotate().Equal(s), true, t)
+	})
+
+	t.Run("X-MAS crosses", func(t *testing.T) {
+		input := ReadInput(strings.NewReader(example))
+		utils.CheckEqual(input.CountStencils(XMasCross.Rotations()...), 9, t)
+		utils.CheckEqual(input.FindStencil(XMasCross), []Point{{1, 0}, {1, 2}}, t)
+	})
+
+	t.Run("plus shapes", func(t *testing.T) {
+		input := ReadInput(strings.NewReader("XMX\nMAS\nXSX"))
+		utils.CheckEqual(input.FindStencil(MasPlus), []Point{{0, 0}}, t)
+		utils.CheckEqual(input.CountStencils(MasPlus.Rotations()...), 1, t)
+		utils.CheckEqual(input.CountStencils(XMasCross.Rotations()...), 0, t)
+	})
+}
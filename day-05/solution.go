@@ -3,11 +3,15 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strconv"
 	"strings"
+
+	"iain.fyi/aoc2024/utils"
 )
 
 var ErrInputFile = errors.New("cannot open input file")
@@ -34,6 +38,30 @@ func (r OrderingRule) Evaluate(pages []int) bool {
 	return slices.Index(pages, r.lower) < slices.Index(pages, r.upper)
 }
 
+// a broken OrderingRule, with the indices of the offending pages in the update
+type Violation struct {
+	Rule       OrderingRule
+	LowerIndex int
+	UpperIndex int
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("rule %v|%v violated: %v at index %v, %v at index %v",
+		v.Rule.lower, v.Rule.upper, v.Rule.lower, v.LowerIndex, v.Rule.upper, v.UpperIndex)
+}
+
+// returns the Violation and true if pages break the rule
+func (r OrderingRule) Violation(pages []int) (Violation, bool) {
+	lowerIndex := slices.Index(pages, r.lower)
+	upperIndex := slices.Index(pages, r.upper)
+
+	if lowerIndex == -1 || upperIndex == -1 || lowerIndex < upperIndex {
+		return Violation{}, false
+	}
+
+	return Violation{Rule: r, LowerIndex: lowerIndex, UpperIndex: upperIndex}, true
+}
+
 type Update struct {
 	pages []int
 }
@@ -43,6 +71,102 @@ func (u Update) MiddlePageValue() int {
 	return u.pages[middlePageIndex]
 }
 
+// all rules broken by the update, in rule order
+func (u Update) Violations(rules []OrderingRule) []Violation {
+	var violations []Violation
+	for _, rule := range rules {
+		if v, broken := rule.Violation(u.pages); broken {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// a single page move; From and To are indices at the time the move is applied
+type Move struct {
+	Page int
+	From int
+	To   int
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("move %v from index %v to index %v", m.Page, m.From, m.To)
+}
+
+// Moves returns the fewest page moves that put the update into the order given by cmp.
+// Applying them in sequence yields the sorted update; the input pages are not modified.
+func (u Update) Moves(cmp func(a, b int) int) []Move {
+	target := slices.Clone(u.pages)
+	slices.SortStableFunc(target, cmp)
+
+	targetIndex := make(map[int]int, len(target))
+	for i, p := range target {
+		targetIndex[p] = i
+	}
+
+	// pages already in the right relative order stay put; everything else moves once
+	keep := longestOrderedRun(utils.Map(u.pages, func(p int) int { return targetIndex[p] }))
+	kept := make(map[int]bool, len(keep))
+	for _, i := range keep {
+		kept[target[i]] = true
+	}
+
+	current := slices.Clone(u.pages)
+	var moves []Move
+
+	for i, page := range target {
+		if kept[page] {
+			continue
+		}
+
+		from := slices.Index(current, page)
+		current = slices.Delete(current, from, from+1)
+
+		// place directly after the page that precedes it in the target order
+		to := 0
+		if i > 0 {
+			to = slices.Index(current, target[i-1]) + 1
+		}
+		current = slices.Insert(current, to, page)
+
+		moves = append(moves, Move{Page: page, From: from, To: to})
+	}
+
+	return moves
+}
+
+// longest strictly increasing subsequence of positions, returned as the values themselves
+func longestOrderedRun(positions []int) []int {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	lengths := make([]int, len(positions))
+	prev := make([]int, len(positions))
+	best := 0
+
+	for i := range positions {
+		lengths[i] = 1
+		prev[i] = -1
+		for j := 0; j < i; j++ {
+			if positions[j] < positions[i] && lengths[j]+1 > lengths[i] {
+				lengths[i] = lengths[j] + 1
+				prev[i] = j
+			}
+		}
+		if lengths[i] > lengths[best] {
+			best = i
+		}
+	}
+
+	var run []int
+	for i := best; i != -1; i = prev[i] {
+		run = append(run, positions[i])
+	}
+	slices.Reverse(run)
+	return run
+}
+
 type Input struct {
 	rules   []OrderingRule
 	updates []Update
@@ -95,8 +219,84 @@ func GetInput(filename string) (*Input, error) {
 	return &Input{rules: rules, updates: updates}, nil
 }
 
+// use rules as a comparator to determine which way around rule-including pages should go
+// if they don't match, it shouldn't matter (e.g. page isn't in the rules)
+func (i *Input) PageComparator() func(a, b int) int {
+	return func(a, b int) int {
+		for _, rule := range i.rules {
+			// if a,b aren't in rule, the rule doesn't care about order
+			if !rule.ShouldEval([]int{a, b}) {
+				continue
+			}
+
+			if a == rule.lower && b == rule.upper {
+				return -1
+			}
+
+			if b == rule.lower && a == rule.upper {
+				return 1
+			}
+
+			return 0
+		}
+		return 0
+	}
+}
+
+// why an update is out of order, and how to fix it
+type Explanation struct {
+	Index      int
+	Update     Update
+	Violations []Violation
+	Moves      []Move
+}
+
+// Explain returns an Explanation for every update that breaks at least one rule
+func (i *Input) Explain() []Explanation {
+	var explanations []Explanation
+	cmp := i.PageComparator()
+
+	for idx, update := range i.updates {
+		violations := update.Violations(i.rules)
+		if len(violations) == 0 {
+			continue
+		}
+
+		explanations = append(explanations, Explanation{
+			Index:      idx,
+			Update:     update,
+			Violations: violations,
+			Moves:      update.Moves(cmp),
+		})
+	}
+
+	return explanations
+}
+
+func PrintExplanations(w io.Writer, explanations []Explanation) {
+	for _, e := range explanations {
+		pages := utils.Map(e.Update.pages, strconv.Itoa)
+		fmt.Fprintf(w, "update %v: %v\n", e.Index, strings.Join(pages, PAGE_SEPARATOR))
+		for _, v := range e.Violations {
+			fmt.Fprintf(w, "  %v\n", v)
+		}
+		for _, m := range e.Moves {
+			fmt.Fprintf(w, "  %v\n", m)
+		}
+	}
+}
+
 func main() {
+	explain := flag.Bool("explain", false, "print violated rules and fixing moves for each out-of-order update")
+	flag.Parse()
+
 	input, _ := GetInput("input.txt")
+
+	if *explain {
+		PrintExplanations(os.Stdout, input.Explain())
+		return
+	}
+
 	p1Result := Part1(input)
 	fmt.Printf("Part 1: got %v\n", p1Result)
 
@@ -114,12 +314,7 @@ func Part1(input *Input) int {
 	middlePageTotal := 0
 
 	for _, update := range input.updates {
-		var ruleOutcomes []bool
-		for _, rule := range input.rules {
-			ruleOutcomes = append(ruleOutcomes, rule.Evaluate(update.pages))
-		}
-
-		if !slices.Contains(ruleOutcomes, false) {
+		if len(update.Violations(input.rules)) == 0 {
 			middlePageTotal += update.MiddlePageValue()
 		}
 	}
@@ -139,41 +334,15 @@ func Part2(input *Input) int {
 	var unorderedUpdates []Update
 
 	for _, update := range input.updates {
-		var ruleOutcomes []bool
-		for _, rule := range input.rules {
-			ruleOutcomes = append(ruleOutcomes, rule.Evaluate(update.pages))
-		}
-
-		if slices.Contains(ruleOutcomes, false) {
+		if len(update.Violations(input.rules)) > 0 {
 			unorderedUpdates = append(unorderedUpdates, update)
 		}
-
 	}
 
-	// use comparator function to determine which way around rule-including pages should go
-	// if they don't match, it shouldn't matter (e.g. page isn't in the rules)
-	sorter := func(a, b int) int {
-		for _, rule := range input.rules {
-			// if a,b aren't in rule, the rule doesn't care about order
-			if !rule.ShouldEval([]int{a, b}) {
-				continue
-			}
-
-			if a == rule.lower && b == rule.upper {
-				return -1
-			}
-
-			if b == rule.lower && a == rule.upper {
-				return 1
-			}
-
-			return 0
-		}
-		return 0
-	}
+	sorter := input.PageComparator()
 
 	for _, update := range unorderedUpdates {
-		pages := update.pages
+		pages := slices.Clone(update.pages)
 		slices.SortFunc(pages, sorter)
 		ordered := Update{pages: pages}
 		middlePageTotal += ordered.MiddlePageValue()
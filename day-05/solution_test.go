@@ -1,6 +1,8 @@
 package main
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"iain.fyi/aoc2024/utils"
@@ -48,3 +50,109 @@ func TestPart2(t *testing.T) {
 
 	utils.CheckEqual(got, want, t)
 }
+
+func exampleInput() *Input {
+	return &Input{
+		rules: []OrderingRule{
+			{47, 53}, {97, 13}, {97, 61}, {97, 47}, {75, 29}, {61, 13}, {75, 53},
+			{29, 13}, {97, 29}, {53, 29}, {61, 53}, {97, 53}, {61, 29}, {47, 13},
+			{75, 47}, {97, 75}, {47, 61}, {75, 61}, {47, 29}, {75, 13}, {53, 13},
+		},
+		updates: []Update{
+			{pages: []int{75, 47, 61, 53, 29}},
+			{pages: []int{97, 61, 53, 29, 13}},
+			{pages: []int{75, 29, 13}},
+			{pages: []int{75, 97, 47, 61, 53}},
+			{pages: []int{61, 13, 29}},
+			{pages: []int{97, 13, 75, 29, 47}},
+		},
+	}
+}
+
+func TestViolations(t *testing.T) {
+	input := exampleInput()
+
+	t.Run("ordered update has no violations", func(t *testing.T) {
+		got := input.updates[0].Violations(input.rules)
+		utils.CheckEqual(len(got), 0, t)
+	})
+
+	t.Run("reports rule and offending indices", func(t *testing.T) {
+		want := []Violation{
+			{Rule: OrderingRule{97, 75}, LowerIndex: 1, UpperIndex: 0},
+		}
+		got := input.updates[3].Violations(input.rules)
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("reports every broken rule", func(t *testing.T) {
+		want := []Violation{
+			{Rule: OrderingRule{29, 13}, LowerIndex: 3, UpperIndex: 1},
+			{Rule: OrderingRule{47, 13}, LowerIndex: 4, UpperIndex: 1},
+			{Rule: OrderingRule{47, 29}, LowerIndex: 4, UpperIndex: 3},
+			{Rule: OrderingRule{75, 13}, LowerIndex: 2, UpperIndex: 1},
+		}
+		got := input.updates[5].Violations(input.rules)
+		utils.CheckEqual(got, want, t)
+	})
+}
+
+func TestMoves(t *testing.T) {
+	input := exampleInput()
+	cmp := input.PageComparator()
+
+	apply := func(pages []int, moves []Move) []int {
+		pages = slices.Clone(pages)
+		for _, m := range moves {
+			pages = slices.Delete(pages, m.From, m.From+1)
+			pages = slices.Insert(pages, m.To, m.Page)
+		}
+		return pages
+	}
+
+	t.Run("single move fixes update", func(t *testing.T) {
+		update := input.updates[3]
+		want := []Move{{Page: 97, From: 1, To: 0}}
+		got := update.Moves(cmp)
+
+		utils.CheckEqual(got, want, t)
+		utils.CheckEqual(apply(update.pages, got), []int{97, 75, 47, 61, 53}, t)
+	})
+
+	t.Run("moves are minimal and sort the update", func(t *testing.T) {
+		update := input.updates[5]
+		got := update.Moves(cmp)
+
+		utils.CheckEqual(len(got), 2, t)
+		utils.CheckEqual(apply(update.pages, got), []int{97, 75, 47, 29, 13}, t)
+	})
+
+	t.Run("does not modify update", func(t *testing.T) {
+		update := Update{pages: []int{61, 13, 29}}
+		update.Moves(cmp)
+		utils.CheckEqual(update.pages, []int{61, 13, 29}, t)
+	})
+}
+
+func TestExplain(t *testing.T) {
+	got := exampleInput().Explain()
+
+	utils.CheckEqual(len(got), 3, t)
+	utils.CheckEqual(got[0].Index, 3, t)
+	utils.CheckEqual(got[1].Index, 4, t)
+	utils.CheckEqual(got[2].Index, 5, t)
+}
+
+func TestPrintExplanations(t *testing.T) {
+	input := exampleInput()
+	input.updates = input.updates[3:4]
+
+	var sb strings.Builder
+	PrintExplanations(&sb, input.Explain())
+
+	want := "update 0: 75,97,47,61,53\n" +
+		"  rule 97|75 violated: 97 at index 1, 75 at index 0\n" +
+		"  move 97 from index 1 to index 0\n"
+
+	utils.CheckEqual(sb.String(), want, t)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+// a lattice point; z is 0 on a flat map
+type Vec3 struct {
+	x, y, z int
+}
+
+func (v Vec3) Add(o Vec3) Vec3 {
+	return Vec3{v.x + o.x, v.y + o.y, v.z + o.z}
+}
+
+func (v Vec3) Sub(o Vec3) Vec3 {
+	return Vec3{v.x - o.x, v.y - o.y, v.z - o.z}
+}
+
+func (v Vec3) Scale(n int) Vec3 {
+	return Vec3{v.x * n, v.y * n, v.z * n}
+}
+
+// divides through by n, if every component divides exactly
+func (v Vec3) Div(n int) (Vec3, bool) {
+	if n == 0 || v.x%n != 0 || v.y%n != 0 || v.z%n != 0 {
+		return Vec3{}, false
+	}
+	return Vec3{v.x / n, v.y / n, v.z / n}, true
+}
+
+func gcd(a, b int) int {
+	a, b = utils.Abs(a), utils.Abs(b)
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// shortest lattice step along v
+func (v Vec3) Reduced() Vec3 {
+	g := gcd(gcd(v.x, v.y), v.z)
+	if g == 0 {
+		return v
+	}
+	step, _ := v.Div(g)
+	return step
+}
+
+func (c Coord) Vec3() Vec3 {
+	return Vec3{c.x, c.y, 0}
+}
+
+func (v Vec3) Coord() Coord {
+	return Coord{v.x, v.y}
+}
+
+// decides where a pair of same-frequency antennas at a and b puts antinodes
+type ResonanceRule interface {
+	Antinodes(a, b Vec3, inBounds func(Vec3) bool) []Vec3
+}
+
+// Ratio puts antinodes at every lattice point in line with both antennas and beyond them
+// where one is Far/Near times as far away as the other. With Between it also puts them
+// at such points between the antennas, e.g. the trisection points for 1:2 or the
+// midpoint for 1:1. Part 1 is Ratio{Near: 1, Far: 2}, outside only. Near must be at least
+// 1 and no more than Far.
+type Ratio struct {
+	Near, Far int
+	Between   bool
+}
+
+func (r Ratio) Antinodes(a, b Vec3, inBounds func(Vec3) bool) []Vec3 {
+	if r.Near < 1 || r.Far < r.Near {
+		return nil
+	}
+
+	var antinodes []Vec3
+	add := func(v Vec3, ok bool) {
+		if ok && inBounds(v) && !slices.Contains(antinodes, v) {
+			antinodes = append(antinodes, v)
+		}
+	}
+
+	for _, near := range []Pair[Vec3]{{a, b}, {b, a}} {
+		diff := near.second.Sub(near.first)
+
+		// beyond the near antenna: p = near - diff * Near/(Far-Near)
+		if r.Far > r.Near {
+			offset, ok := diff.Scale(r.Near).Div(r.Far - r.Near)
+			add(near.first.Sub(offset), ok)
+		}
+
+		// between them: p = near + diff * Near/(Near+Far)
+		if r.Between {
+			offset, ok := diff.Scale(r.Near).Div(r.Near + r.Far)
+			add(near.first.Add(offset), ok)
+		}
+	}
+
+	return antinodes
+}
+
+// Resonant puts antinodes at every lattice point in line with both antennas, including the
+// antennas themselves (Part 2). Stepping by the gcd-reduced difference catches points
+// between the antennas as well as beyond them.
+type Resonant struct{}
+
+func (Resonant) Antinodes(a, b Vec3, inBounds func(Vec3) bool) []Vec3 {
+	step := b.Sub(a).Reduced()
+	if step == (Vec3{}) {
+		return nil
+	}
+
+	var antinodes []Vec3
+	for p := a; inBounds(p); p = p.Add(step) {
+		antinodes = append(antinodes, p)
+	}
+	for p := a.Sub(step); inBounds(p); p = p.Sub(step) {
+		antinodes = append(antinodes, p)
+	}
+	return antinodes
+}
+
+// size of the map; Depth is 1 for a flat map
+type Bounds struct {
+	Width, Height, Depth int
+}
+
+func (b Bounds) Contains(v Vec3) bool {
+	return v.x >= 0 && v.x < b.Width &&
+		v.y >= 0 && v.y < b.Height &&
+		v.z >= 0 && v.z < b.Depth
+}
+
+// AntennaMap holds antenna positions grouped by frequency, so only antennas that can
+// resonate with each other are ever paired up.
+type AntennaMap struct {
+	frequencies map[string][]Vec3
+	bounds      Bounds
+}
+
+// ReadAntennaMap reads a map in the puzzle's format. Several grids separated by blank
+// lines are stacked as layers of a 3D map, the first at z = 0.
+func ReadAntennaMap(r io.Reader) AntennaMap {
+	am := AntennaMap{frequencies: make(map[string][]Vec3)}
+
+	scanner := bufio.NewScanner(r)
+	y, z := 0, 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if y > 0 {
+				y, z = 0, z+1
+			}
+			continue
+		}
+
+		for x, c := range strings.Split(line, "") {
+			if c != "." {
+				am.frequencies[c] = append(am.frequencies[c], Vec3{x, y, z})
+			}
+		}
+		am.bounds.Width = max(am.bounds.Width, len(line))
+		y++
+		am.bounds.Height = max(am.bounds.Height, y)
+		am.bounds.Depth = z + 1
+	}
+
+	return am
+}
+
+// antennas from the PointMap, grouped by frequency
+func (pm *PointMap) AntennaMap() AntennaMap {
+	am := AntennaMap{frequencies: make(map[string][]Vec3), bounds: Bounds{Depth: 1}}
+	for coord, point := range pm.m {
+		if point.IsAntenna() {
+			am.frequencies[point.symbol] = append(am.frequencies[point.symbol], coord.Vec3())
+		}
+		am.bounds.Width = max(am.bounds.Width, coord.x+1)
+		am.bounds.Height = max(am.bounds.Height, coord.y+1)
+	}
+	// map order isn't stable; keep antennas in reading order
+	for _, antennas := range am.frequencies {
+		slices.SortFunc(antennas, func(a, b Vec3) int {
+			return cmp.Or(cmp.Compare(a.z, b.z), cmp.Compare(a.y, b.y), cmp.Compare(a.x, b.x))
+		})
+	}
+	return am
+}
+
+func (am AntennaMap) Frequencies() []string {
+	return slices.Sorted(maps.Keys(am.frequencies))
+}
+
+// every in-bounds antinode from pairs of same-frequency antennas
+func (am AntennaMap) Antinodes(rule ResonanceRule) map[Vec3]bool {
+	antinodes := make(map[Vec3]bool)
+	for _, antennas := range am.frequencies {
+		for i, a := range antennas {
+			for _, b := range antennas[i+1:] {
+				for _, v := range rule.Antinodes(a, b, am.bounds.Contains) {
+					antinodes[v] = true
+				}
+			}
+		}
+	}
+	return antinodes
+}
+
+func (am AntennaMap) CountAntinodes(rule ResonanceRule) int {
+	return len(am.Antinodes(rule))
+}
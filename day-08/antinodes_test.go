@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+const example = `............
+........0...
+.....0......
+.......0....
+....0.......
+......A.....
+............
+............
+........A...
+.........A..
+............
+............`
+
+func TestAntennaMap(t *testing.T) {
+	am := ReadAntennaMap(strings.NewReader(example))
+
+	utils.CheckEqual(am.Frequencies(), []string{"0", "A"}, t)
+	utils.CheckEqual(am.bounds, Bounds{12, 12, 1}, t)
+	utils.CheckEqual(am.CountAntinodes(Ratio{Near: 1, Far: 2}), 14, t)
+	utils.CheckEqual(am.CountAntinodes(Resonant{}), 34, t)
+
+	t.Run("matches the point map", func(t *testing.T) {
+		pointMap := PointMap{m: make(map[Coord]Point)}
+		for y, line := range strings.Split(example, "\n") {
+			for x, c := range strings.Split(line, "") {
+				pointMap.Put(Coord{x, y}, Point{symbol: c})
+			}
+		}
+
+		utils.CheckEqual(pointMap.AntennaMap(), am, t)
+		utils.CheckEqual(Part1(&Input{pointMap: pointMap}), 14, t)
+		utils.CheckEqual(len(pointMap.GetUniqueAntinodes()), 14, t)
+	})
+}
+
+func TestRatio(t *testing.T) {
+	everywhere := func(Vec3) bool { return true }
+
+	t.Run("1:2 outside", func(t *testing.T) {
+		got := Ratio{Near: 1, Far: 2}.Antinodes(Vec3{4, 4, 0}, Vec3{6, 6, 0}, everywhere)
+		utils.CheckSlicesHaveSameElements(got, []Vec3{{2, 2, 0}, {8, 8, 0}}, t)
+	})
+
+	t.Run("1:2 leaves out trisection points", func(t *testing.T) {
+		got := Ratio{Near: 1, Far: 2}.Antinodes(Vec3{0, 0, 0}, Vec3{3, 6, 0}, everywhere)
+		utils.CheckSlicesHaveSameElements(got, []Vec3{{-3, -6, 0}, {6, 12, 0}}, t)
+	})
+
+	t.Run("1:2 between includes trisection points", func(t *testing.T) {
+		got := Ratio{Near: 1, Far: 2, Between: true}.Antinodes(Vec3{0, 0, 0}, Vec3{3, 6, 0}, everywhere)
+		utils.CheckSlicesHaveSameElements(got, []Vec3{{-3, -6, 0}, {1, 2, 0}, {6, 12, 0}, {2, 4, 0}}, t)
+	})
+
+	t.Run("1:3 between", func(t *testing.T) {
+		got := Ratio{Near: 1, Far: 3, Between: true}.Antinodes(Vec3{0, 0, 0}, Vec3{8, 4, 0}, everywhere)
+		utils.CheckSlicesHaveSameElements(got, []Vec3{{-4, -2, 0}, {2, 1, 0}, {6, 3, 0}, {12, 6, 0}}, t)
+	})
+
+	t.Run("1:1 between is the midpoint", func(t *testing.T) {
+		got := Ratio{Near: 1, Far: 1, Between: true}.Antinodes(Vec3{0, 0, 0}, Vec3{2, 4, 6}, everywhere)
+		utils.CheckEqual(got, []Vec3{{1, 2, 3}}, t)
+
+		utils.CheckEqual(len(Ratio{Near: 1, Far: 1}.Antinodes(Vec3{0, 0, 0}, Vec3{2, 4, 6}, everywhere)), 0, t)
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		inside := Bounds{10, 10, 1}.Contains
+		got := Ratio{Near: 1, Far: 2}.Antinodes(Vec3{1, 1, 0}, Vec3{5, 5, 0}, inside)
+		utils.CheckEqual(got, []Vec3{{9, 9, 0}}, t)
+	})
+
+	t.Run("invalid ratio", func(t *testing.T) {
+		utils.CheckEqual(len(Ratio{Near: 2, Far: 1}.Antinodes(Vec3{}, Vec3{1, 1, 1}, everywhere)), 0, t)
+		utils.CheckEqual(len(Ratio{Near: 0, Far: 1}.Antinodes(Vec3{}, Vec3{1, 1, 1}, everywhere)), 0, t)
+	})
+
+	t.Run("Part 1 only counts points outside the antennas", func(t *testing.T) {
+		pointMap := PointMap{m: make(map[Coord]Point)}
+		for y := range 10 {
+			for x := range 10 {
+				pointMap.Put(Coord{x, y}, Point{symbol: "."})
+			}
+		}
+		pointMap.Put(Coord{0, 0}, Point{symbol: "a"})
+		pointMap.Put(Coord{3, 3}, Point{symbol: "a"})
+
+		// (6, 6) only; (-3, -3) is off the map
+		utils.CheckEqual(Part1(&Input{pointMap: pointMap}), 1, t)
+		// adding (1, 1) and (2, 2)
+		utils.CheckEqual(pointMap.AntennaMap().CountAntinodes(Ratio{Near: 1, Far: 2, Between: true}), 3, t)
+	})
+}
+
+func TestResonant3D(t *testing.T) {
+	am := ReadAntennaMap(strings.NewReader("a...\n....\n....\n\n....\n....\n....\n\n....\n....\n..a.\n\n....\n....\n....\n"))
+
+	utils.CheckEqual(am.bounds, Bounds{4, 3, 4}, t)
+	utils.CheckEqual(am.frequencies["a"], []Vec3{{0, 0, 0}, {2, 2, 2}}, t)
+
+	got := Resonant{}.Antinodes(Vec3{0, 0, 0}, Vec3{2, 2, 2}, am.bounds.Contains)
+	utils.CheckSlicesHaveSameElements(got, []Vec3{{0, 0, 0}, {1, 1, 1}, {2, 2, 2}}, t)
+	utils.CheckEqual(am.CountAntinodes(Resonant{}), 3, t)
+	utils.CheckEqual(am.CountAntinodes(Ratio{Near: 1, Far: 2}), 0, t)
+}
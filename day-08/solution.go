@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
 	"strings"
+
+	"iain.fyi/aoc2024/utils"
 )
 
 var ErrInputFile = errors.New("cannot open input file")
@@ -76,16 +79,24 @@ func (pm *PointMap) Put(coord Coord, point Point) {
 
 type Input struct {
 	pointMap PointMap
+	// the antennas when the input has several layers, which the 2D point map can't hold
+	layers *AntennaMap
 }
 
+// GetInput reads a map in the puzzle's format. Several grids separated by blank lines are
+// read as layers of a 3D map (see ReadAntennaMap), which Part1 and Part2 then solve in 3D.
 func GetInput(filename string) (*Input, error) {
-	file, err := os.Open(filename)
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, ErrInputFile
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	var layers *AntennaMap
+	if am := ReadAntennaMap(bytes.NewReader(data)); am.bounds.Depth > 1 {
+		layers = &am
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 
 	pointMap := PointMap{
 		m: make(map[Coord]Point),
@@ -103,7 +114,7 @@ func GetInput(filename string) (*Input, error) {
 		y += 1
 	}
 
-	return &Input{pointMap}, nil
+	return &Input{pointMap: pointMap, layers: layers}, nil
 }
 
 func main() {
@@ -119,16 +130,12 @@ type Pair[T any] struct {
 	first, second T
 }
 
+// each unordered pair once, i.e. (a, b) but not also (b, a)
 func GetAllUniquePairs(coords []Coord) []Pair[Coord] {
 	var antennaPairs []Pair[Coord]
 
 	for i, a := range coords {
-		leftSlice := coords[:i]
-		for _, a2 := range leftSlice {
-			antennaPairs = append(antennaPairs, Pair[Coord]{a, a2})
-		}
-		rightSlice := coords[i+1:]
-		for _, a2 := range rightSlice {
+		for _, a2 := range coords[i+1:] {
 			antennaPairs = append(antennaPairs, Pair[Coord]{a, a2})
 		}
 	}
@@ -153,97 +160,32 @@ func GetAntinodes(pair Pair[Coord]) []Coord {
 	return []Coord{first, second}
 }
 
+// every point on the map in line with the pair, including the antennas themselves
 func GetAntinodesWithResonantHarmonics(pair Pair[Coord], pm PointMap) []Coord {
-	var antinodeCoords []Coord
-
-	// Need to also include teh antennas
-	antinodeCoords = append(antinodeCoords, pair.first)
-	antinodeCoords = append(antinodeCoords, pair.second)
-
-	xDiff := pair.first.x - pair.second.x
-	yDiff := pair.first.y - pair.second.y
-
-	positive := Coord{
-		x: pair.first.x + xDiff,
-		y: pair.first.y + yDiff,
-	}
-	positivePoint := pm.Get(positive)
-	// keep adding coords in positive direction until they out out-of-bounds
-	for positivePoint != nil {
-		antinodeCoords = append(antinodeCoords, positive)
-		positive = Coord{
-			x: positive.x + xDiff,
-			y: positive.y + yDiff,
-		}
-		positivePoint = pm.Get(positive)
-	}
+	inBounds := func(v Vec3) bool { return v.z == 0 && pm.Get(v.Coord()) != nil }
+	antinodes := Resonant{}.Antinodes(pair.first.Vec3(), pair.second.Vec3(), inBounds)
+	return utils.Map(antinodes, Vec3.Coord)
+}
 
-	negative := Coord{
-		x: pair.second.x - xDiff,
-		y: pair.second.y - yDiff,
+// mark every antinode the rule gives in the map, returning how many there are
+func markAntinodes(pm *PointMap, rule ResonanceRule) int {
+	antinodes := pm.AntennaMap().Antinodes(rule)
+	for an := range antinodes {
+		pm.SetAntinodeIfInBounds(an.Coord())
 	}
-	negativePoint := pm.Get(negative)
-	// keep adding coords in negative direction until they out out-of-bounds
-	for negativePoint != nil {
-		antinodeCoords = append(antinodeCoords, negative)
-		negative = Coord{
-			x: positive.x + xDiff,
-			y: positive.y + yDiff,
-		}
-		negativePoint = pm.Get(positive)
-	}
-
-	return antinodeCoords
+	return len(antinodes)
 }
 
 func Part1(input *Input) int {
-	// 1: Get all antennas
-	// 2: Get all unique pairs
-	// 3: For each pair, if same frequency, get antinodes
-	// 4: If antinode in bounds, set antinode flag in map
-	// 4: Count unique antinodes
-
-	antennas := input.pointMap.GetAntennas()
-	aPairs := GetAllUniquePairs(antennas)
-
-	for _, pair := range aPairs {
-		first := input.pointMap.Get(pair.first)
-		second := input.pointMap.Get(pair.second)
-
-		if first.SameFrequency(second) {
-			antinodes := GetAntinodes(pair)
-			for _, an := range antinodes {
-				input.pointMap.SetAntinodeIfInBounds(an)
-			}
-		}
+	if input.layers != nil {
+		return input.layers.CountAntinodes(Ratio{Near: 1, Far: 2})
 	}
-
-	antinodes := input.pointMap.GetUniqueAntinodes()
-	return len(antinodes)
+	return markAntinodes(&input.pointMap, Ratio{Near: 1, Far: 2})
 }
 
 func Part2(input *Input) int {
-	// 1: Get all antennas
-	// 2: Get all unique pairs
-	// 3: For each pair, if same frequency, get all resonant antinodes
-	// 4: For each antinode in bounds, set antinode flag in map (including on Antenna)
-	// 4: Count unique antinodes
-
-	antennas := input.pointMap.GetAntennas()
-	aPairs := GetAllUniquePairs(antennas)
-
-	for _, pair := range aPairs {
-		first := input.pointMap.Get(pair.first)
-		second := input.pointMap.Get(pair.second)
-
-		if first.SameFrequency(second) {
-			antinodes := GetAntinodesWithResonantHarmonics(pair, input.pointMap)
-			for _, an := range antinodes {
-				input.pointMap.SetAntinodeIfInBounds(an)
-			}
-		}
+	if input.layers != nil {
+		return input.layers.CountAntinodes(Resonant{})
 	}
-
-	antinodes := input.pointMap.GetUniqueAntinodes()
-	return len(antinodes)
+	return markAntinodes(&input.pointMap, Resonant{})
 }
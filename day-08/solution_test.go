@@ -1,6 +1,9 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"iain.fyi/aoc2024/utils"
@@ -107,15 +110,9 @@ func TestGetAllUniquePairs(t *testing.T) {
 		{Coord{0, 0}, Coord{0, 1}},
 		{Coord{0, 0}, Coord{1, 0}},
 		{Coord{0, 0}, Coord{1, 1}},
-		{Coord{0, 1}, Coord{0, 0}},
 		{Coord{0, 1}, Coord{1, 0}},
 		{Coord{0, 1}, Coord{1, 1}},
-		{Coord{1, 0}, Coord{0, 0}},
-		{Coord{1, 0}, Coord{0, 1}},
 		{Coord{1, 0}, Coord{1, 1}},
-		{Coord{1, 1}, Coord{0, 0}},
-		{Coord{1, 1}, Coord{0, 1}},
-		{Coord{1, 1}, Coord{1, 0}},
 	}
 
 	got := GetAllUniquePairs(input)
@@ -166,7 +163,57 @@ func TestGetAntinodes(t *testing.T) {
 }
 
 func TestGetAntinodesWithResonantHarmonics(t *testing.T) {
+	pointMap := PointMap{m: make(map[Coord]Point)}
+	for y := range 10 {
+		for x := range 10 {
+			pointMap.Put(Coord{x, y}, Point{symbol: "."})
+		}
+	}
+
+	t.Run("extends both ways", func(t *testing.T) {
+		got := GetAntinodesWithResonantHarmonics(Pair[Coord]{Coord{3, 3}, Coord{4, 5}}, pointMap)
+		utils.CheckSlicesHaveSameElements(got, []Coord{{2, 1}, {3, 3}, {4, 5}, {5, 7}, {6, 9}}, t)
+	})
+
+	t.Run("catches points between the antennas", func(t *testing.T) {
+		got := GetAntinodesWithResonantHarmonics(Pair[Coord]{Coord{2, 2}, Coord{6, 4}}, pointMap)
+		utils.CheckSlicesHaveSameElements(got, []Coord{{0, 1}, {2, 2}, {4, 3}, {6, 4}, {8, 5}}, t)
+	})
+}
+
+func TestLayeredInput(t *testing.T) {
+	layer := func(rows ...string) string { return strings.Join(rows, "\n") + "\n" }
+	text := layer("a...", "....", "....", "....") + "\n" +
+		layer("....", ".a..", "....", "....") + "\n" +
+		layer("....", "....", "....", "....") + "\n" +
+		layer("....", "....", "....", "....")
 
+	filename := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(filename, []byte(text), 0o644); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	input, err := GetInput(filename)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	utils.CheckEqual(input.layers.bounds, Bounds{4, 4, 4}, t)
+	// (2, 2, 2); (-1, -1, -1) is outside
+	utils.CheckEqual(Part1(input), 1, t)
+	// the diagonal from (0, 0, 0) to (3, 3, 3)
+	utils.CheckEqual(Part2(input), 4, t)
+
+	t.Run("a single grid stays 2D", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "input.txt")
+		if err := os.WriteFile(filename, []byte(layer("a...", ".a..", "....", "....")+"\n"), 0o644); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		input, _ := GetInput(filename)
+
+		utils.CheckEqual(input.layers == nil, true, t)
+		utils.CheckEqual(Part1(input), 1, t)
+		utils.CheckEqual(Part2(input), 4, t)
+	})
 }
 
 func TestPart1(t *testing.T) {
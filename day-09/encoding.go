@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrLayout = errors.New("invalid disk layout")
+
+// largest run a single diskmap digit can hold
+const MAX_RUN = 9
+
+// free space marker in a layout
+const FREE = "."
+
+// run-length view of the blocks, merging neighbouring blocks of the same file
+func (bod BlocksOnDisk) ToExtents() ExtentDisk {
+	var files []Extent
+	for pos, b := range bod.blocks {
+		fb, ok := b.(FileBlock)
+		if !ok {
+			continue
+		}
+
+		if n := len(files); n > 0 && files[n-1].ID == fb.id && files[n-1].End() == pos {
+			files[n-1].Length++
+		} else {
+			files = append(files, Extent{ID: fb.id, Start: pos, Length: 1})
+		}
+	}
+	return ExtentDisk{files: files, size: len(bod.blocks)}
+}
+
+func (bod BlocksOnDisk) ToDiskmap() Diskmap {
+	return bod.ToExtents().ToDiskmap()
+}
+
+// ToDiskmap encodes the disk in the dense [file, space, file, ...] format. Runs longer
+// than 9 are split with zero-length entries in between. File IDs are only recorded if
+// they differ from the puzzle's 0, 1, 2, ... numbering.
+func (d ExtentDisk) ToDiskmap() Diskmap {
+	var series, ids []int
+
+	// add a run, keeping the file/space alternation and splitting long runs
+	emit := func(id, length int) {
+		isFile := id != -1
+		for {
+			if wantFile := len(series)%2 == 0; wantFile != isFile {
+				if wantFile {
+					ids = append(ids, len(series)/2)
+				}
+				series = append(series, 0)
+			}
+			if isFile {
+				ids = append(ids, id)
+			}
+
+			n := min(length, MAX_RUN)
+			series = append(series, n)
+			length -= n
+			if length == 0 {
+				return
+			}
+		}
+	}
+
+	pos := 0
+	for _, f := range d.files {
+		if f.Start > pos {
+			emit(-1, f.Start-pos)
+		}
+		emit(f.ID, f.Length)
+		pos = f.End()
+	}
+	if pos < d.size {
+		emit(-1, d.size-pos)
+	}
+
+	for i, id := range ids {
+		if id != i {
+			return Diskmap{series: series, ids: ids}
+		}
+	}
+	return Diskmap{series: series}
+}
+
+// the dense digits; file IDs aren't included
+func (d Diskmap) String() string {
+	var sb strings.Builder
+	for _, n := range d.series {
+		sb.WriteString(strconv.Itoa(n))
+	}
+	return sb.String()
+}
+
+// Layout is an unambiguous text form of the disk: space-separated `<id>x<length>` runs,
+// with `.` as the ID of free space, e.g. "0x2 .x3 10x1".
+func (d ExtentDisk) Layout() string {
+	var runs []string
+	run := func(id string, length int) {
+		runs = append(runs, id+"x"+strconv.Itoa(length))
+	}
+
+	pos := 0
+	for _, f := range d.files {
+		if f.Start > pos {
+			run(FREE, f.Start-pos)
+		}
+		run(strconv.Itoa(f.ID), f.Length)
+		pos = f.End()
+	}
+	if pos < d.size {
+		run(FREE, d.size-pos)
+	}
+
+	return strings.Join(runs, " ")
+}
+
+func ParseLayout(layout string) (ExtentDisk, error) {
+	var files []Extent
+
+	pos := 0
+	for _, run := range strings.Fields(layout) {
+		idPart, lengthPart, found := strings.Cut(run, "x")
+		length, err := strconv.Atoi(lengthPart)
+		if !found || err != nil || length < 1 {
+			return ExtentDisk{}, fmt.Errorf("%w: bad run %q", ErrLayout, run)
+		}
+
+		if idPart != FREE {
+			id, err := strconv.Atoi(idPart)
+			if err != nil || id < 0 {
+				return ExtentDisk{}, fmt.Errorf("%w: bad file ID in %q", ErrLayout, run)
+			}
+			files = append(files, Extent{ID: id, Start: pos, Length: length})
+		}
+		pos += length
+	}
+
+	return ExtentDisk{files: files, size: pos}, nil
+}
+
+type extentDiskJSON struct {
+	Size  int      `json:"size"`
+	Files []Extent `json:"files"`
+}
+
+func (d ExtentDisk) MarshalJSON() ([]byte, error) {
+	files := d.files
+	if files == nil {
+		files = []Extent{}
+	}
+	return json.Marshal(extentDiskJSON{Size: d.size, Files: files})
+}
+
+func (d *ExtentDisk) UnmarshalJSON(data []byte) error {
+	var decoded extentDiskJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	disk := NewExtentDisk(decoded.Files, decoded.Size)
+	pos := 0
+	for _, f := range disk.files {
+		if f.ID < 0 || f.Length < 1 || f.Start < pos {
+			return fmt.Errorf("%w: bad or overlapping extent %+v", ErrLayout, f)
+		}
+		pos = f.End()
+	}
+	if pos > disk.size {
+		return fmt.Errorf("%w: extents run past size %v", ErrLayout, disk.size)
+	}
+
+	*d = disk
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestToDiskmap(t *testing.T) {
+	t.Run("round-trips the puzzle example", func(t *testing.T) {
+		diskmap := Diskmap{
+			series: []int{2, 3, 3, 3, 1, 3, 3, 1, 2, 1, 4, 1, 4, 1, 3, 1, 4, 0, 2},
+		}
+
+		got := diskmap.ToBlocks().ToDiskmap()
+
+		utils.CheckEqual(got.String(), "2333133121414131402", t)
+		utils.CheckEqual(got.ids, []int(nil), t)
+	})
+
+	t.Run("splits runs longer than 9", func(t *testing.T) {
+		disk, _ := ParseLayout("0x12 .x10 1x1")
+
+		got := disk.ToDiskmap()
+
+		utils.CheckEqual(got.series, []int{9, 0, 3, 9, 0, 1, 1}, t)
+		// the 0-length file splitting the space takes its place's ID
+		utils.CheckEqual(got.ids, []int{0, 0, 2, 1}, t)
+	})
+
+	t.Run("records IDs out of order", func(t *testing.T) {
+		disk, _ := ParseLayout("0x2 9x2 8x1 .x3 12x1")
+
+		got := disk.ToDiskmap()
+
+		utils.CheckEqual(got.series, []int{2, 0, 2, 0, 1, 3, 1}, t)
+		utils.CheckEqual(got.ids, []int{0, 9, 8, 12}, t)
+	})
+
+	t.Run("starts with free space", func(t *testing.T) {
+		disk, _ := ParseLayout(".x2 1x1")
+
+		got := disk.ToDiskmap()
+
+		utils.CheckEqual(got.series, []int{0, 2, 1}, t)
+		utils.CheckEqual(got.ids, []int(nil), t)
+	})
+
+	t.Run("lossless for any disk", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(3, 3))
+		for range 200 {
+			var blocks []Block
+			for range r.IntN(10) {
+				length := 1 + r.IntN(20)
+				var block Block = Space{}
+				if r.IntN(3) > 0 {
+					block = FileBlock{id: r.IntN(30)}
+				}
+				for range length {
+					blocks = append(blocks, block)
+				}
+			}
+			before := BlocksOnDisk{blocks: blocks}
+
+			diskmap := before.ToDiskmap()
+			for _, n := range diskmap.series {
+				if n > MAX_RUN {
+					t.Fatalf("digit %v too large in %v", n, diskmap.series)
+				}
+			}
+
+			after := diskmap.ToBlocks()
+			utils.CheckEqual(after.ToExtents().Layout(), before.ToExtents().Layout(), t)
+			utils.CheckEqual(len(after.blocks), len(before.blocks), t)
+		}
+	})
+}
+
+func TestLayout(t *testing.T) {
+	t.Run("Layout() handles multi-digit IDs", func(t *testing.T) {
+		bod := BlocksOnDisk{blocks: []Block{
+			FileBlock{id: 1}, FileBlock{id: 11}, Space{}, Space{}, FileBlock{id: 1}, FileBlock{id: 1},
+		}}
+
+		utils.CheckEqual(bod.Print(), "111..11", t)
+		utils.CheckEqual(bod.ToExtents().Layout(), "1x1 11x1 .x2 1x2", t)
+	})
+
+	t.Run("ParseLayout() round-trips", func(t *testing.T) {
+		layout := ".x1 0x2 .x3 10x1 3x4 .x2"
+
+		disk, err := ParseLayout(layout)
+
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(disk.size, 13, t)
+		utils.CheckEqual(disk.Layout(), layout, t)
+	})
+
+	t.Run("ParseLayout() rejects bad runs", func(t *testing.T) {
+		for _, layout := range []string{"0x", "0-2", "ax2", "0x0", "-1x2", ".x-1"} {
+			_, err := ParseLayout(layout)
+			utils.CheckEqual(errors.Is(err, ErrLayout), true, t)
+		}
+	})
+
+	t.Run("fixtures as before/after layouts", func(t *testing.T) {
+		before, _ := ParseLayout("0x2 .x3 1x3 .x3 2x1 .x3 3x3 .x1 4x2 .x1 5x4 .x1 6x4 .x1 7x3 .x1 8x4 9x2")
+		want := "0x2 9x2 2x1 1x3 7x3 .x1 4x2 .x1 3x3 .x4 5x4 .x1 6x4 .x5 8x4 .x2"
+
+		utils.CheckEqual(FirstFit{}.Compact(before).Layout(), want, t)
+	})
+}
+
+func TestExtentDiskJSON(t *testing.T) {
+	t.Run("round-trips", func(t *testing.T) {
+		disk, _ := ParseLayout("0x2 .x1 12x3 .x2")
+
+		data, err := json.Marshal(disk)
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(string(data), `{"size":8,"files":[{"id":0,"start":0,"length":2},{"id":12,"start":3,"length":3}]}`, t)
+
+		var decoded ExtentDisk
+		err = json.Unmarshal(data, &decoded)
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(decoded, disk, t)
+	})
+
+	t.Run("rejects overlapping extents", func(t *testing.T) {
+		var decoded ExtentDisk
+		err := json.Unmarshal([]byte(`{"size":4,"files":[{"id":0,"start":0,"length":2},{"id":1,"start":1,"length":2}]}`), &decoded)
+
+		utils.CheckEqual(errors.Is(err, ErrLayout), true, t)
+	})
+
+	t.Run("rejects extents past the end", func(t *testing.T) {
+		var decoded ExtentDisk
+		err := json.Unmarshal([]byte(`{"size":1,"files":[{"id":0,"start":0,"length":2}]}`), &decoded)
+
+		utils.CheckEqual(errors.Is(err, ErrLayout), true, t)
+	})
+}
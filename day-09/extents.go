@@ -0,0 +1,276 @@
+package main
+
+import (
+	"cmp"
+	"container/heap"
+	"iter"
+	"slices"
+)
+
+// a run of consecutive blocks belonging to the same file
+type Extent struct {
+	ID     int `json:"id"`
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+func (e Extent) End() int {
+	return e.Start + e.Length
+}
+
+// sum of position * ID over every block in the extent
+func (e Extent) Checksum() int {
+	return e.ID * (e.Length*e.Start + e.Length*(e.Length-1)/2)
+}
+
+// Run-length model of the disk: each file is one or more extents, and anything not
+// covered by an extent is free space.
+type ExtentDisk struct {
+	// sorted by Start
+	files []Extent
+	size  int
+}
+
+func NewExtentDisk(files []Extent, size int) ExtentDisk {
+	sorted := slices.Clone(files)
+	slices.SortFunc(sorted, func(a, b Extent) int { return cmp.Compare(a.Start, b.Start) })
+	return ExtentDisk{files: sorted, size: size}
+}
+
+func (d *Diskmap) ToExtents() ExtentDisk {
+	var files []Extent
+
+	pos := 0
+	// it goes [file, space, file, space, ...]
+	for idx, n := range d.series {
+		if idx%2 == 0 && n > 0 {
+			files = append(files, Extent{ID: d.fileID(idx), Start: pos, Length: n})
+		}
+		pos += n
+	}
+
+	return ExtentDisk{files: files, size: pos}
+}
+
+// free spans left-to-right, with ID -1
+func (d ExtentDisk) FreeSpans() []Extent {
+	var spans []Extent
+
+	pos := 0
+	for _, f := range d.files {
+		if f.Start > pos {
+			spans = append(spans, Extent{ID: -1, Start: pos, Length: f.Start - pos})
+		}
+		pos = f.End()
+	}
+	if pos < d.size {
+		spans = append(spans, Extent{ID: -1, Start: pos, Length: d.size - pos})
+	}
+
+	return spans
+}
+
+func (d ExtentDisk) Checksum() int {
+	checksum := 0
+	for _, f := range d.files {
+		checksum += f.Checksum()
+	}
+	return checksum
+}
+
+// expand into one Block per sector, e.g. for Print()
+func (d ExtentDisk) ToBlocks() BlocksOnDisk {
+	blocks := make([]Block, d.size)
+	for i := range blocks {
+		blocks[i] = Space{}
+	}
+	for _, f := range d.files {
+		for pos := f.Start; pos < f.End(); pos++ {
+			blocks[pos] = FileBlock{id: f.ID}
+		}
+	}
+	return BlocksOnDisk{blocks: blocks}
+}
+
+// Compact moves blocks one at a time from the end of the disk into the leftmost free
+// space, splitting files as needed. It's a single pass over files and free spans.
+func (d ExtentDisk) Compact() ExtentDisk {
+	free := d.FreeSpans()
+	var compacted []Extent
+
+	freeIdx := 0
+	// rightmost file first, moving its tail first
+	for _, f := range slices.Backward(d.files) {
+		remaining := f.Length
+
+		for remaining > 0 && freeIdx < len(free) && free[freeIdx].Start < f.Start {
+			span := &free[freeIdx]
+			n := min(span.Length, remaining)
+
+			compacted = append(compacted, Extent{ID: f.ID, Start: span.Start, Length: n})
+			span.Start += n
+			span.Length -= n
+			remaining -= n
+
+			if span.Length == 0 {
+				freeIdx++
+			}
+		}
+
+		if remaining > 0 {
+			compacted = append(compacted, Extent{ID: f.ID, Start: f.Start, Length: remaining})
+		}
+	}
+
+	return NewExtentDisk(compacted, d.size)
+}
+
+// CompactContiguousFiles moves whole files, highest ID first, into the leftmost free span
+// they fit in.
+func (d ExtentDisk) CompactContiguousFiles() ExtentDisk {
+	return d.compactWholeFiles(pickFirstFit)
+}
+
+// picks the length of free span a file of length goes into, or -1 to leave it in place;
+// only spans starting before limit can be used
+type spanPicker func(free *freeSpace, length, limit int) int
+
+func pickFirstFit(free *freeSpace, length, limit int) int {
+	best := -1
+	for l := range free.lengthsFrom(length) {
+		if free.usable(l, limit) && (best == -1 || free.buckets[l][0] < free.buckets[best][0]) {
+			best = l
+		}
+	}
+	return best
+}
+
+// free space while whole files are moved. Spans are bucketed by length, each bucket a
+// min-heap of start positions, so a pick only looks at the top of each bucket. Spans
+// that merge or get used are only dropped from the maps; their bucket entries go stale
+// and are thrown away when they reach the top.
+type freeSpace struct {
+	// start -> length of each free span
+	lengthAt map[int]int
+	// end -> start of each free span
+	startBefore map[int]int
+	buckets     []startHeap
+	// live spans of each length, and the lengths that have any in order, so picks skip
+	// empty buckets
+	counts  []int
+	lengths []int
+}
+
+func newFreeSpace(spans []Extent) *freeSpace {
+	fs := &freeSpace{lengthAt: make(map[int]int), startBefore: make(map[int]int)}
+	for _, span := range spans {
+		fs.add(span.Start, span.Length)
+	}
+	return fs
+}
+
+// lengths with live spans, at least min, shortest first
+func (fs *freeSpace) lengthsFrom(min int) iter.Seq[int] {
+	i, _ := slices.BinarySearch(fs.lengths, min)
+	return slices.Values(fs.lengths[i:])
+}
+
+// usable reports whether the leftmost span of the length starts before limit. Files only
+// move left, so only spans left of the file being moved count.
+func (fs *freeSpace) usable(length, limit int) bool {
+	bucket := &fs.buckets[length]
+	// drop stale entries from the top
+	for len(*bucket) > 0 && fs.lengthAt[(*bucket)[0]] != length {
+		heap.Pop(bucket)
+	}
+	return len(*bucket) > 0 && (*bucket)[0] < limit
+}
+
+func (fs *freeSpace) remove(start, length int) {
+	delete(fs.lengthAt, start)
+	delete(fs.startBefore, start+length)
+	if fs.counts[length]--; fs.counts[length] == 0 {
+		i, _ := slices.BinarySearch(fs.lengths, length)
+		fs.lengths = slices.Delete(fs.lengths, i, i+1)
+	}
+}
+
+// add frees a span, merging it with free spans either side
+func (fs *freeSpace) add(start, length int) {
+	if left, ok := fs.startBefore[start]; ok {
+		fs.remove(left, start-left)
+		length += start - left
+		start = left
+	}
+	if right, ok := fs.lengthAt[start+length]; ok {
+		fs.remove(start+length, right)
+		length += right
+	}
+
+	fs.lengthAt[start] = length
+	fs.startBefore[start+length] = start
+	for len(fs.buckets) <= length {
+		fs.buckets = append(fs.buckets, nil)
+		fs.counts = append(fs.counts, 0)
+	}
+	heap.Push(&fs.buckets[length], start)
+	if fs.counts[length]++; fs.counts[length] == 1 {
+		i, _ := slices.BinarySearch(fs.lengths, length)
+		fs.lengths = slices.Insert(fs.lengths, i, length)
+	}
+}
+
+// take uses the first used blocks of the leftmost span of the length, freeing the rest
+func (fs *freeSpace) take(length, used int) int {
+	start := heap.Pop(&fs.buckets[length]).(int)
+	fs.remove(start, length)
+	if rest := length - used; rest > 0 {
+		fs.add(start+used, rest)
+	}
+	return start
+}
+
+// compactWholeFiles moves whole files, highest ID first, into the free span chosen by
+// pick. The space a file leaves is freed again, so later files can use it.
+func (d ExtentDisk) compactWholeFiles(pick spanPicker) ExtentDisk {
+	free := newFreeSpace(d.FreeSpans())
+
+	files := slices.Clone(d.files)
+	slices.SortFunc(files, func(a, b Extent) int {
+		return cmp.Or(cmp.Compare(b.ID, a.ID), cmp.Compare(b.Start, a.Start))
+	})
+
+	var compacted []Extent
+	for _, f := range files {
+		best := pick(free, f.Length, f.Start)
+		if best == -1 {
+			compacted = append(compacted, f)
+			continue
+		}
+
+		start := free.take(best, f.Length)
+		compacted = append(compacted, Extent{ID: f.ID, Start: start, Length: f.Length})
+		free.add(f.Start, f.Length)
+	}
+
+	return NewExtentDisk(compacted, d.size)
+}
+
+// min-heap of free span start positions
+type startHeap []int
+
+func (h startHeap) Len() int           { return len(h) }
+func (h startHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h startHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *startHeap) Push(x any) {
+	*h = append(*h, x.(int))
+}
+
+func (h *startHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
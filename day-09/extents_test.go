@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestExtentDisk(t *testing.T) {
+	diskmap := Diskmap{
+		series: []int{2, 3, 3, 3, 1, 3, 3, 1, 2, 1, 4, 1, 4, 1, 3, 1, 4, 0, 2},
+	}
+
+	t.Run("ToExtents()", func(t *testing.T) {
+		got := (&Diskmap{series: []int{1, 2, 3, 4, 5}}).ToExtents()
+		want := ExtentDisk{
+			files: []Extent{{0, 0, 1}, {1, 3, 3}, {2, 10, 5}},
+			size:  15,
+		}
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("ToExtents() skips empty files", func(t *testing.T) {
+		got := (&Diskmap{series: []int{1, 1, 0, 2, 1}}).ToExtents()
+
+		utils.CheckEqual(got.files, []Extent{{0, 0, 1}, {2, 4, 1}}, t)
+		utils.CheckEqual(got.ToBlocks().Print(), "0...2", t)
+	})
+
+	t.Run("FreeSpans()", func(t *testing.T) {
+		got := (&Diskmap{series: []int{1, 2, 3, 4, 5, 1}}).ToExtents().FreeSpans()
+		want := []Extent{{-1, 1, 2}, {-1, 6, 4}, {-1, 15, 1}}
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("ToBlocks() then Print()", func(t *testing.T) {
+		want := "00...111...2...333.44.5555.6666.777.888899"
+		got := diskmap.ToExtents().ToBlocks().Print()
+
+		utils.CheckEqual(got, want, t)
+	})
+
+	t.Run("Compact()", func(t *testing.T) {
+		compacted := diskmap.ToExtents().Compact()
+
+		utils.CheckEqual(compacted.ToBlocks().Print(), "0099811188827773336446555566..............", t)
+		utils.CheckEqual(compacted.Checksum(), 1928, t)
+	})
+
+	t.Run("CompactContiguousFiles()", func(t *testing.T) {
+		compacted := diskmap.ToExtents().CompactContiguousFiles()
+
+		utils.CheckEqual(compacted.ToBlocks().Print(), "00992111777.44.333....5555.6666.....8888..", t)
+		utils.CheckEqual(compacted.Checksum(), 2858, t)
+	})
+
+	t.Run("agrees with block-by-block compaction", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(9, 9))
+		for range 50 {
+			series := make([]int, 1+r.IntN(40))
+			for i := range series {
+				series[i] = r.IntN(10)
+			}
+			series[0] = 1 + r.IntN(9)
+			dm := Diskmap{series: series}
+
+			utils.CheckEqual(dm.ToExtents().Compact().Checksum(), compactOneBlockAtATime(dm.ToBlocks()).Checksum(), t)
+			utils.CheckEqual(
+				dm.ToExtents().CompactContiguousFiles().ToBlocks().Print(),
+				dm.ToBlocks().CompactContiguousFiles().Print(),
+				t,
+			)
+		}
+	})
+
+	t.Run("CompactContiguousFiles() with IDs out of order", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(3, 3))
+		for range 200 {
+			var files []Extent
+			pos := 0
+			for _, id := range r.Perm(1 + r.IntN(12)) {
+				pos += r.IntN(4)
+				files = append(files, Extent{ID: id, Start: pos, Length: 1 + r.IntN(4)})
+				pos += files[len(files)-1].Length
+			}
+			disk := NewExtentDisk(files, pos+r.IntN(4))
+
+			utils.CheckEqual(disk.CompactContiguousFiles().ToBlocks().Print(), compactWholeFilesOneAtATime(disk.ToBlocks()).Print(), t)
+		}
+	})
+}
+
+// reference block compaction that's easy to trust
+func compactOneBlockAtATime(bod BlocksOnDisk) BlocksOnDisk {
+	blocks := slices.Clone(bod.blocks)
+	isSpace := func(b Block) bool { return b.Symbol() == "." }
+
+	tip, tail := 0, len(blocks)-1
+	for {
+		for tip < tail && !isSpace(blocks[tip]) {
+			tip++
+		}
+		for tip < tail && isSpace(blocks[tail]) {
+			tail--
+		}
+		if tip >= tail {
+			return BlocksOnDisk{blocks: blocks}
+		}
+		Swap(blocks, tip, tail)
+	}
+}
+
+// reference whole-file compaction that's easy to trust: for each file, highest ID first,
+// scan from the front for the first run of space it fits in
+func compactWholeFilesOneAtATime(bod BlocksOnDisk) BlocksOnDisk {
+	blocks := slices.Clone(bod.blocks)
+	isSpace := func(b Block) bool { return b.Symbol() == "." }
+
+	ids := BlocksOnDisk{blocks: blocks}.UniqueFileIds()
+	slices.Sort(ids)
+	for _, id := range slices.Backward(ids) {
+		start := slices.IndexFunc(blocks, func(b Block) bool { return !isSpace(b) && b.(FileBlock).id == id })
+		end := start
+		for end < len(blocks) && !isSpace(blocks[end]) && blocks[end].(FileBlock).id == id {
+			end++
+		}
+
+		run := 0
+		for pos := 0; pos < start; pos++ {
+			if !isSpace(blocks[pos]) {
+				run = 0
+				continue
+			}
+			if run++; run == end-start {
+				for n := range run {
+					Swap(blocks, pos-run+1+n, start+n)
+				}
+				break
+			}
+		}
+	}
+
+	return BlocksOnDisk{blocks: blocks}
+}
+
+func BenchmarkCompactContiguousFiles(b *testing.B) {
+	r := rand.New(rand.NewPCG(1, 2))
+	series := make([]int, 4001)
+	for i := range series {
+		series[i] = 1 + r.IntN(9)
+	}
+	diskmap := Diskmap{series: series}
+
+	b.Run("blocks", func(b *testing.B) {
+		for range b.N {
+			diskmap.ToBlocks().CompactContiguousFiles().Checksum()
+		}
+	})
+
+	b.Run("extents", func(b *testing.B) {
+		for range b.N {
+			diskmap.ToExtents().CompactContiguousFiles().Checksum()
+		}
+	})
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"slices"
@@ -20,6 +21,16 @@ type Input struct {
 
 type Diskmap struct {
 	series []int
+	// file ID of each file entry; nil means IDs count up from 0, as in the puzzle
+	ids []int
+}
+
+// ID of the file at series[idx]
+func (d *Diskmap) fileID(idx int) int {
+	if d.ids != nil {
+		return d.ids[idx/2]
+	}
+	return idx / 2
 }
 
 type Block interface {
@@ -47,17 +58,15 @@ type BlocksOnDisk struct {
 func (d *Diskmap) ToBlocks() BlocksOnDisk {
 	var blocks []Block
 
-	id := 0
 	// for each digit
 	for idx, n := range d.series {
 		// it goes [file, space, file, space, ...]
 		if idx%2 == 0 {
 			// n files, each with same ID
 			for range n {
-				block := FileBlock{id}
+				block := FileBlock{d.fileID(idx)}
 				blocks = append(blocks, block)
 			}
-			id += 1
 		} else {
 			for range n {
 				block := Space{}
@@ -69,6 +78,7 @@ func (d *Diskmap) ToBlocks() BlocksOnDisk {
 	return BlocksOnDisk{blocks: blocks}
 }
 
+// puzzle-style rendering; IDs of 10 or more make it ambiguous, see Layout() instead
 func (bod BlocksOnDisk) Print() string {
 	var chars []string
 	for _, b := range bod.blocks {
@@ -281,7 +291,18 @@ func (bod BlocksOnDisk) Checksum() int {
 }
 
 func main() {
+	compare := flag.Bool("compare", false, "report how each compaction strategy handles the disk")
+	flag.Parse()
+
 	input, _ := GetInput("input.txt")
+
+	if *compare {
+		for _, report := range CompareStrategies(input.diskmap.ToExtents(), Strategies...) {
+			fmt.Println(report)
+		}
+		return
+	}
+
 	p1Result := Part1(input)
 	fmt.Printf("Part 1: got %v\n", p1Result)
 
@@ -312,16 +333,16 @@ func GetInput(filename string) (*Input, error) {
 }
 
 func Part1(input *Input) int {
-	blocks := input.diskmap.ToBlocks()
-	compacted := blocks.Compact()
+	extents := input.diskmap.ToExtents()
+	compacted := extents.Compact()
 	checksum := compacted.Checksum()
 
 	return checksum
 }
 
 func Part2(input *Input) int {
-	blocks := input.diskmap.ToBlocks()
-	contiguous := blocks.CompactContiguousFiles()
+	extents := input.diskmap.ToExtents()
+	contiguous := extents.CompactContiguousFiles()
 	checksum := contiguous.Checksum()
 
 	return checksum
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+// a way of moving files towards the front of the disk
+type CompactionStrategy interface {
+	Name() string
+	Compact(d ExtentDisk) ExtentDisk
+}
+
+// moves blocks one at a time, splitting files (Part 1)
+type BlockByBlock struct{}
+
+func (BlockByBlock) Name() string                    { return "block-by-block" }
+func (BlockByBlock) Compact(d ExtentDisk) ExtentDisk { return d.Compact() }
+
+// moves whole files into the leftmost span they fit in (Part 2)
+type FirstFit struct{}
+
+func (FirstFit) Name() string                    { return "first-fit" }
+func (FirstFit) Compact(d ExtentDisk) ExtentDisk { return d.compactWholeFiles(pickFirstFit) }
+
+// moves whole files into the smallest span they fit in, leftmost on ties
+type BestFit struct{}
+
+func (BestFit) Name() string                    { return "best-fit" }
+func (BestFit) Compact(d ExtentDisk) ExtentDisk { return d.compactWholeFiles(pickBestFit) }
+
+// moves whole files into the largest span they fit in, leftmost on ties
+type WorstFit struct{}
+
+func (WorstFit) Name() string                    { return "worst-fit" }
+func (WorstFit) Compact(d ExtentDisk) ExtentDisk { return d.compactWholeFiles(pickWorstFit) }
+
+// packs every extent against the front of the disk in its current order, leaving a
+// single free span at the end
+type DefragmentToFront struct{}
+
+func (DefragmentToFront) Name() string { return "defragment-to-front" }
+
+func (DefragmentToFront) Compact(d ExtentDisk) ExtentDisk {
+	var packed []Extent
+
+	pos := 0
+	for _, f := range d.files {
+		// merge with the previous extent if it's the same file
+		if n := len(packed); n > 0 && packed[n-1].ID == f.ID {
+			packed[n-1].Length += f.Length
+		} else {
+			packed = append(packed, Extent{ID: f.ID, Start: pos, Length: f.Length})
+		}
+		pos += f.Length
+	}
+
+	return NewExtentDisk(packed, d.size)
+}
+
+func pickBestFit(free *freeSpace, length, limit int) int {
+	for l := range free.lengthsFrom(length) {
+		if free.usable(l, limit) {
+			return l
+		}
+	}
+	return -1
+}
+
+func pickWorstFit(free *freeSpace, length, limit int) int {
+	worst := -1
+	for l := range free.lengthsFrom(length) {
+		if free.usable(l, limit) {
+			worst = l
+		}
+	}
+	return worst
+}
+
+var Strategies = []CompactionStrategy{
+	BlockByBlock{},
+	FirstFit{},
+	BestFit{},
+	WorstFit{},
+	DefragmentToFront{},
+}
+
+// how a disk looks after a CompactionStrategy has run over it
+type CompactionReport struct {
+	Strategy string
+	Checksum int
+	// blocks that ended up somewhere other than where they started
+	MovedBlocks int
+	// files split over more than one extent
+	FragmentedFiles int
+	// 1 - largest free span / total free space; 0 when free space is one span
+	FreeFragmentation float64
+	// free span length -> number of spans that long
+	FreeSpanHistogram map[int]int
+}
+
+func (r CompactionReport) String() string {
+	lengths := slices.Sorted(maps.Keys(r.FreeSpanHistogram))
+	histogram := make([]string, 0, len(lengths))
+	for _, l := range lengths {
+		histogram = append(histogram, fmt.Sprintf("%v:%v", l, r.FreeSpanHistogram[l]))
+	}
+
+	return fmt.Sprintf("%v: checksum %v, moved %v blocks, %v fragmented files, free fragmentation %.3f, free spans [%v]",
+		r.Strategy, r.Checksum, r.MovedBlocks, r.FragmentedFiles, r.FreeFragmentation, strings.Join(histogram, " "))
+}
+
+func RunStrategy(s CompactionStrategy, d ExtentDisk) CompactionReport {
+	compacted := s.Compact(d)
+
+	report := CompactionReport{
+		Strategy:          s.Name(),
+		Checksum:          compacted.Checksum(),
+		MovedBlocks:       MovedBlocks(d, compacted),
+		FreeSpanHistogram: make(map[int]int),
+	}
+
+	extentsPerFile := make(map[int]int)
+	for _, f := range compacted.files {
+		extentsPerFile[f.ID]++
+	}
+	for _, count := range extentsPerFile {
+		if count > 1 {
+			report.FragmentedFiles++
+		}
+	}
+
+	totalFree, largestFree := 0, 0
+	for _, span := range compacted.FreeSpans() {
+		report.FreeSpanHistogram[span.Length]++
+		totalFree += span.Length
+		largestFree = max(largestFree, span.Length)
+	}
+	if totalFree > 0 {
+		report.FreeFragmentation = 1 - float64(largestFree)/float64(totalFree)
+	}
+
+	return report
+}
+
+// run every strategy over the same disk
+func CompareStrategies(d ExtentDisk, strategies ...CompactionStrategy) []CompactionReport {
+	return utils.Map(strategies, func(s CompactionStrategy) CompactionReport {
+		return RunStrategy(s, d)
+	})
+}
+
+// MovedBlocks counts blocks of each file that aren't where they were before.
+func MovedBlocks(before, after ExtentDisk) int {
+	previous := make(map[int][]Extent)
+	for _, f := range before.files {
+		previous[f.ID] = append(previous[f.ID], f)
+	}
+
+	moved := 0
+	for _, f := range after.files {
+		stayed := 0
+		for _, p := range previous[f.ID] {
+			stayed += max(0, min(f.End(), p.End())-max(f.Start, p.Start))
+		}
+		moved += f.Length - stayed
+	}
+	return moved
+}
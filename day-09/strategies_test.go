@@ -0,0 +1,97 @@
+package main
+
+import (
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestCompactionStrategies(t *testing.T) {
+	diskmap := Diskmap{
+		series: []int{2, 3, 3, 3, 1, 3, 3, 1, 2, 1, 4, 1, 4, 1, 3, 1, 4, 0, 2},
+	}
+	disk := diskmap.ToExtents()
+
+	t.Run("block-by-block and first-fit match the puzzle", func(t *testing.T) {
+		utils.CheckEqual(BlockByBlock{}.Compact(disk).Checksum(), 1928, t)
+		utils.CheckEqual(FirstFit{}.Compact(disk).Checksum(), 2858, t)
+	})
+
+	t.Run("best-fit prefers the smallest span", func(t *testing.T) {
+		small := (&Diskmap{series: []int{1, 3, 1, 1, 1, 1, 1}}).ToExtents()
+
+		utils.CheckEqual(small.ToBlocks().Print(), "0...1.2.3", t)
+		utils.CheckEqual(FirstFit{}.Compact(small).ToBlocks().Print(), "0321.....", t)
+		utils.CheckEqual(BestFit{}.Compact(small).ToBlocks().Print(), "021..3...", t)
+	})
+
+	t.Run("worst-fit prefers the largest span", func(t *testing.T) {
+		small := (&Diskmap{series: []int{1, 1, 1, 3, 1, 1, 1}}).ToExtents()
+
+		utils.CheckEqual(small.ToBlocks().Print(), "0.1...2.3", t)
+		utils.CheckEqual(FirstFit{}.Compact(small).ToBlocks().Print(), "0312.....", t)
+		utils.CheckEqual(WorstFit{}.Compact(small).ToBlocks().Print(), "01.32....", t)
+	})
+
+	t.Run("IDs out of order", func(t *testing.T) {
+		// moving 3 frees space that 2, 1 and 0 then use
+		disk, err := ParseLayout(".x4 3x3 .x2 0x2 1x1 .x2 2x1 .x2")
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		utils.CheckEqual(FirstFit{}.Compact(disk).ToBlocks().Print(), "3332100..........", t)
+		utils.CheckEqual(FirstFit{}.Compact(disk).Checksum(), 19, t)
+		utils.CheckEqual(BestFit{}.Compact(disk).ToBlocks().Print(), "333100......2....", t)
+		utils.CheckEqual(WorstFit{}.Compact(disk).ToBlocks().Print(), "3332100..........", t)
+	})
+
+	t.Run("defragment-to-front packs everything", func(t *testing.T) {
+		got := DefragmentToFront{}.Compact(disk).ToBlocks().Print()
+
+		utils.CheckEqual(got, "0011123334455556666777888899..............", t)
+	})
+}
+
+func TestRunStrategy(t *testing.T) {
+	diskmap := Diskmap{
+		series: []int{2, 3, 3, 3, 1, 3, 3, 1, 2, 1, 4, 1, 4, 1, 3, 1, 4, 0, 2},
+	}
+	disk := diskmap.ToExtents()
+
+	t.Run("first-fit", func(t *testing.T) {
+		// 00992111777.44.333....5555.6666.....8888..
+		largest, total := 5.0, 14.0
+		want := CompactionReport{
+			Strategy:          "first-fit",
+			Checksum:          2858,
+			MovedBlocks:       8,
+			FragmentedFiles:   0,
+			FreeFragmentation: 1 - largest/total,
+			FreeSpanHistogram: map[int]int{1: 3, 2: 1, 4: 1, 5: 1},
+		}
+
+		utils.CheckEqual(RunStrategy(FirstFit{}, disk), want, t)
+	})
+
+	t.Run("block-by-block", func(t *testing.T) {
+		got := RunStrategy(BlockByBlock{}, disk)
+
+		utils.CheckEqual(got.MovedBlocks, 12, t)
+		utils.CheckEqual(got.FragmentedFiles, 2, t)
+		utils.CheckEqual(got.FreeFragmentation, 0.0, t)
+		utils.CheckEqual(got.FreeSpanHistogram, map[int]int{14: 1}, t)
+	})
+
+	t.Run("CompareStrategies() runs each in order", func(t *testing.T) {
+		got := utils.Map(CompareStrategies(disk, Strategies...), func(r CompactionReport) string { return r.Strategy })
+
+		utils.CheckEqual(got, []string{"block-by-block", "first-fit", "best-fit", "worst-fit", "defragment-to-front"}, t)
+	})
+
+	t.Run("String()", func(t *testing.T) {
+		got := RunStrategy(DefragmentToFront{}, disk).String()
+
+		utils.CheckEqual(got, "defragment-to-front: checksum 2453, moved 26 blocks, 0 fragmented files, free fragmentation 0.000, free spans [14:1]", t)
+	})
+}
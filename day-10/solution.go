@@ -4,12 +4,10 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
-	"maps"
+	"io"
 	"os"
 	"strconv"
 	"strings"
-
-	"iain.fyi/aoc2024/utils"
 )
 
 var ErrInputFile = errors.New("cannot open input file")
@@ -17,14 +15,29 @@ var ErrInputFile = errors.New("cannot open input file")
 type Node struct {
 	height int
 	next   []*Node
-}
-
-func (n *Node) IsValidNext(on *Node) bool {
-	return n.height-on.height == 1
+	x, y   int
+	// position in Graph.nodes
+	index int
 }
 
 type Graph struct {
 	trailheads []*Node
+	// every node, in reading order
+	nodes []*Node
+	grid  [][]*Node
+	rules TrailRules
+}
+
+func (g *Graph) isSummit(n *Node) bool {
+	return n.height != IMPASSABLE && n.height == g.rules.End
+}
+
+// node at x, y, or nil if off the map
+func (g *Graph) At(x, y int) *Node {
+	if y < 0 || y >= len(g.grid) || x < 0 || x >= len(g.grid[y]) {
+		return nil
+	}
+	return g.grid[y][x]
 }
 
 // Marking trail with head and final node
@@ -32,66 +45,29 @@ type Result struct {
 	head, finish *Node
 }
 
-// Walk from each trailhead, return count of finishes per trailhead
+// Walk returns one Result per trailhead and summit reachable from it
 func (g *Graph) Walk() []Result {
+	counts := g.CountTrails()
 
-	// collection of trailhead, X occurrences = X finishes
 	var results []Result
-	collect := func(r Result) {
-		results = append(results, r)
-	}
-
-	// map of Node -> finishes
-	// var scores map[*Node]int
-
-	var dfs func(head *Node, n *Node, collector func(r Result))
-	dfs = func(head *Node, currNode *Node, collector func(R Result)) {
-		if currNode.height == 9 {
-			result := Result{head: head, finish: currNode}
-			collector(result)
-		}
-		for _, nn := range currNode.next {
-			dfs(head, nn, collector)
-		}
-	}
-
-	// run DFS from each trailhead
 	for _, th := range g.trailheads {
-		dfs(th, th, collect)
-	}
-
-	resultCounts := utils.CountOccurences(results)
-	var uniqueResults []Result
-	for r := range maps.Keys(resultCounts) {
-		uniqueResults = append(uniqueResults, r)
+		for _, summit := range counts.Summits(th) {
+			results = append(results, Result{head: th, finish: summit})
+		}
 	}
 
-	return uniqueResults
+	return results
 }
 
-// Walk from each trailhead, return count of finishes per trailhead
+// WalkNonUnique returns the number of trails from each trailhead that has any
 func (g *Graph) WalkNonUnique() map[*Node]int {
+	counts := g.CountTrails()
 
-	// Map of *Node -> number of 9s in trails (e.g. finishes)
 	nodeFinishes := make(map[*Node]int)
-	recordNodeFinishes := func(head *Node) {
-		old := nodeFinishes[head]
-		nodeFinishes[head] = old + 1
-	}
-
-	var dfs func(head *Node, n *Node, collector func(h *Node))
-	dfs = func(head *Node, currNode *Node, collector func(h *Node)) {
-		if currNode.height == 9 {
-			collector(head)
-		}
-		for _, nn := range currNode.next {
-			dfs(head, nn, collector)
-		}
-	}
-
-	// run DFS from each trailhead
 	for _, th := range g.trailheads {
-		dfs(th, th, recordNodeFinishes)
+		if rating := counts.Rating(th); rating > 0 {
+			nodeFinishes[th] = rating
+		}
 	}
 
 	return nodeFinishes
@@ -117,78 +93,79 @@ func GetInput(filename string) (*Input, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	graph, err := ReadGraph(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Input{graph: *graph}, nil
+}
+
+func ReadGraph(r io.Reader) (*Graph, error) {
+	return ReadGraphWithRules(r, DefaultRules)
+}
+
+// ReadGraphWithRules reads a map of single-digit heights; anything else ('.' in the
+// puzzle's examples) is impassable. Nodes link to neighbours that rules allows a step to.
+func ReadGraphWithRules(r io.Reader, rules TrailRules) (*Graph, error) {
+	scanner := bufio.NewScanner(r)
 	var trailheads []*Node
+	var nodes []*Node
 
 	var nodeMatrix [][]*Node
 
 	// build initial nodeMatrix
+	y := 0
 	for scanner.Scan() {
 		line := scanner.Text()
 		var nodeLine []*Node
-		for _, c := range strings.Split(line, "") {
-			h, _ := strconv.Atoi(c)
-			n := &Node{height: h}
-			if n.height == 0 {
+		for x, c := range strings.Split(line, "") {
+			h, err := strconv.Atoi(c)
+			if err != nil {
+				h = IMPASSABLE
+			}
+			n := &Node{height: h, x: x, y: y, index: len(nodes)}
+			if n.height != IMPASSABLE && n.height == rules.Start {
 				trailheads = append(trailheads, n)
 			}
 			nodeLine = append(nodeLine, n)
+			nodes = append(nodes, n)
 		}
 		nodeMatrix = append(nodeMatrix, nodeLine)
+		y++
 	}
 
-	// set up neighbours
-	for outerIdx, nodeLine := range nodeMatrix {
-		for innerIdx, currNode := range nodeLine {
-			// left
-			if innerIdx > 0 {
-				left := nodeLine[innerIdx-1]
-				if left.IsValidNext(currNode) {
-					currNode.next = append(currNode.next, left)
-				}
-			}
+	graph := &Graph{trailheads: trailheads, nodes: nodes, grid: nodeMatrix, rules: rules}
 
-			// right
-			if innerIdx < len(nodeLine)-1 {
-				right := nodeLine[innerIdx+1]
-				if right.IsValidNext(currNode) {
-					currNode.next = append(currNode.next, right)
-				}
-			}
-
-			// up
-			if outerIdx > 0 {
-				up := nodeMatrix[outerIdx-1][innerIdx]
-				if up.IsValidNext(currNode) {
-					currNode.next = append(currNode.next, up)
-				}
-			}
+	// set up neighbours
+	for _, currNode := range nodes {
+		if currNode.height == IMPASSABLE {
+			continue
+		}
 
-			// down
-			if outerIdx < len(nodeMatrix)-1 {
-				down := nodeMatrix[outerIdx+1][innerIdx]
-				if down.IsValidNext(currNode) {
-					currNode.next = append(currNode.next, down)
-				}
+		for _, offset := range rules.Connectivity.offsets() {
+			nn := graph.At(currNode.x+offset[0], currNode.y+offset[1])
+			if nn != nil && nn.height != IMPASSABLE && rules.Step(currNode.height, nn.height) {
+				currNode.next = append(currNode.next, nn)
 			}
 		}
 	}
 
-	return &Input{graph: Graph{trailheads: trailheads}}, nil
+	return graph, scanner.Err()
 }
 
 func Part1(input *Input) int {
-	results := input.graph.Walk()
-	return len(results)
+	score := 0
+	for _, r := range input.graph.Reports() {
+		score += r.Score
+	}
+	return score
 }
 
 func Part2(input *Input) int {
-	results := input.graph.WalkNonUnique()
-
-	fullCount := 0
-	for _, count := range results {
-		fullCount += count
+	rating := 0
+	for _, r := range input.graph.Reports() {
+		rating += r.Rating
 	}
-
-	return fullCount
+	return rating
 }
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// height given to cells that can't be walked on
+const IMPASSABLE = -1
+
+type Connectivity int
+
+const (
+	FourWay  Connectivity = 4
+	EightWay Connectivity = 8
+)
+
+// x, y offsets of the neighbours; left, right, up, down, then diagonals
+func (c Connectivity) offsets() [][2]int {
+	orthogonal := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	if c != EightWay {
+		return orthogonal
+	}
+	return append(orthogonal, [2]int{-1, -1}, [2]int{1, -1}, [2]int{-1, 1}, [2]int{1, 1})
+}
+
+// TrailRules says what makes a trail: it starts on a Start height, ends on an End
+// height, and every step between neighbours passes Step. Step must not allow a trail
+// to loop back on itself (e.g. it should always go strictly up or strictly down).
+type TrailRules struct {
+	Start, End   int
+	Step         func(from, to int) bool
+	Connectivity Connectivity
+}
+
+// the puzzle's rules: 0 up to 9, one height at a time, no diagonals
+var DefaultRules = TrailRules{
+	Start:        0,
+	End:          9,
+	Step:         func(from, to int) bool { return to-from == 1 },
+	Connectivity: FourWay,
+}
+
+type Coord struct {
+	X, Y int
+}
+
+// set of summits, one bit per summit
+type summitSet []uint64
+
+func newSummitSet(summits int) summitSet {
+	return make(summitSet, (summits+63)/64)
+}
+
+func (s summitSet) add(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s summitSet) union(other summitSet) {
+	for i, word := range other {
+		s[i] |= word
+	}
+}
+
+func (s summitSet) has(i int) bool {
+	return s[i/64]&(1<<(i%64)) != 0
+}
+
+func (s summitSet) count() int {
+	n := 0
+	for _, word := range s {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// TrailCounts holds, for every node, the summits reachable from it and the number of
+// distinct trails from it to any summit. Both are indexed by Node.index.
+type TrailCounts struct {
+	summits []summitSet
+	trails  []int
+	// summit nodes, in the order of their bits in a summitSet
+	summitNodes []*Node
+}
+
+// CountTrails fills in TrailCounts with one pass over the nodes from the summits down.
+// Nodes are visited in reverse topological order (for the puzzle's rules, descending
+// height), so everything a node links to is done before it: O(nodes * summits/64) for
+// the summit sets, O(nodes) for the trails.
+func (g *Graph) CountTrails() TrailCounts {
+	counts := TrailCounts{
+		summits: make([]summitSet, len(g.nodes)),
+		trails:  make([]int, len(g.nodes)),
+	}
+
+	summitIndex := make(map[*Node]int)
+	for _, n := range g.nodes {
+		if g.isSummit(n) {
+			summitIndex[n] = len(counts.summitNodes)
+			counts.summitNodes = append(counts.summitNodes, n)
+		}
+	}
+
+	for _, n := range slices.Backward(g.topologicalOrder()) {
+		reachable := newSummitSet(len(summitIndex))
+		counts.summits[n.index] = reachable
+
+		// a trail stops at the first summit it reaches
+		if g.isSummit(n) {
+			reachable.add(summitIndex[n])
+			counts.trails[n.index] = 1
+			continue
+		}
+
+		for _, nn := range n.next {
+			reachable.union(counts.summits[nn.index])
+			counts.trails[n.index] += counts.trails[nn.index]
+		}
+	}
+
+	return counts
+}
+
+// nodes ordered so each comes before everything it links to (Kahn's algorithm)
+func (g *Graph) topologicalOrder() []*Node {
+	incoming := make([]int, len(g.nodes))
+	for _, n := range g.nodes {
+		for _, nn := range n.next {
+			incoming[nn.index]++
+		}
+	}
+
+	var order []*Node
+	for _, n := range g.nodes {
+		if incoming[n.index] == 0 {
+			order = append(order, n)
+		}
+	}
+
+	for i := 0; i < len(order); i++ {
+		for _, nn := range order[i].next {
+			incoming[nn.index]--
+			if incoming[nn.index] == 0 {
+				order = append(order, nn)
+			}
+		}
+	}
+
+	return order
+}
+
+// number of summits reachable from n
+func (c TrailCounts) Score(n *Node) int {
+	return c.summits[n.index].count()
+}
+
+// summits reachable from n, in reading order
+func (c TrailCounts) Summits(n *Node) []*Node {
+	var summits []*Node
+	for i, summit := range c.summitNodes {
+		if c.summits[n.index].has(i) {
+			summits = append(summits, summit)
+		}
+	}
+	return summits
+}
+
+// number of distinct trails from n
+func (c TrailCounts) Rating(n *Node) int {
+	return c.trails[n.index]
+}
+
+type TrailheadReport struct {
+	X, Y   int
+	Score  int
+	Rating int
+}
+
+// score and rating of every trailhead, in reading order
+func (g *Graph) Reports() []TrailheadReport {
+	counts := g.CountTrails()
+
+	var reports []TrailheadReport
+	for _, th := range g.trailheads {
+		reports = append(reports, TrailheadReport{
+			X:      th.x,
+			Y:      th.y,
+			Score:  counts.Score(th),
+			Rating: counts.Rating(th),
+		})
+	}
+	return reports
+}
+
+// Trails enumerates every trail from head to a summit as the coordinates walked.
+func (g *Graph) Trails(head *Node) iter.Seq[[]Coord] {
+	return func(yield func([]Coord) bool) {
+		var path []Coord
+
+		var dfs func(n *Node) bool
+		dfs = func(n *Node) bool {
+			path = append(path, Coord{n.x, n.y})
+			defer func() { path = path[:len(path)-1] }()
+
+			if g.isSummit(n) {
+				return yield(slices.Clone(path))
+			}
+			for _, nn := range n.next {
+				if !dfs(nn) {
+					return false
+				}
+			}
+			return true
+		}
+
+		dfs(head)
+	}
+}
+
+// Render draws the map showing only cells on a trail from head, as the puzzle's
+// examples do; everything else is '.'.
+func (g *Graph) Render(head *Node) string {
+	onTrail := make(map[Coord]bool)
+	for trail := range g.Trails(head) {
+		for _, c := range trail {
+			onTrail[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	for y, row := range g.grid {
+		for x, n := range row {
+			if onTrail[Coord{x, y}] {
+				sb.WriteString(strconv.Itoa(n.height))
+			} else {
+				sb.WriteString(".")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
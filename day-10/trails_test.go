@@ -0,0 +1,195 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+const exampleMap = `89010123
+78121874
+87430965
+96549874
+45678903
+32019012
+01329801
+10456732`
+
+func TestCountTrails(t *testing.T) {
+	graph, err := ReadGraph(strings.NewReader(exampleMap))
+	utils.CheckEqual(err, nil, t)
+
+	reports := graph.Reports()
+
+	t.Run("scores", func(t *testing.T) {
+		got := utils.Map(reports, func(r TrailheadReport) int { return r.Score })
+		utils.CheckEqual(got, []int{5, 6, 5, 3, 1, 3, 5, 3, 5}, t)
+	})
+
+	t.Run("ratings", func(t *testing.T) {
+		got := utils.Map(reports, func(r TrailheadReport) int { return r.Rating })
+		utils.CheckEqual(got, []int{20, 24, 10, 4, 1, 4, 5, 8, 5}, t)
+	})
+
+	t.Run("trailhead positions", func(t *testing.T) {
+		utils.CheckEqual(reports[0], TrailheadReport{X: 2, Y: 0, Score: 5, Rating: 20}, t)
+		utils.CheckEqual(reports[8], TrailheadReport{X: 1, Y: 7, Score: 5, Rating: 5}, t)
+	})
+
+	t.Run("parts", func(t *testing.T) {
+		input := &Input{graph: *graph}
+
+		utils.CheckEqual(Part1(input), 36, t)
+		utils.CheckEqual(Part2(input), 81, t)
+	})
+
+	t.Run("agrees with Walk()", func(t *testing.T) {
+		utils.CheckEqual(len(graph.Walk()), 36, t)
+
+		rating := 0
+		for _, finishes := range graph.WalkNonUnique() {
+			rating += finishes
+		}
+		utils.CheckEqual(rating, 81, t)
+	})
+
+	t.Run("Walk() stops at a summit below the top", func(t *testing.T) {
+		rules := DefaultRules
+		rules.End = 5
+		low, _ := ReadGraphWithRules(strings.NewReader(exampleMap), rules)
+
+		score, rating := 0, 0
+		for _, r := range low.Reports() {
+			score += r.Score
+			rating += r.Rating
+		}
+		utils.CheckEqual(len(low.Walk()), score, t)
+
+		walked := 0
+		for _, finishes := range low.WalkNonUnique() {
+			walked += finishes
+		}
+		utils.CheckEqual(walked, rating, t)
+	})
+
+	t.Run("more than 64 summits", func(t *testing.T) {
+		var sb strings.Builder
+		sb.WriteString(strings.Repeat("9", 100) + "\n")
+		sb.WriteString(strings.Repeat("8", 100) + "\n")
+		for h := 7; h >= 0; h-- {
+			sb.WriteString(strings.Repeat(string(rune('0'+h)), 100) + "\n")
+		}
+
+		wide, _ := ReadGraph(strings.NewReader(sb.String()))
+		counts := wide.CountTrails()
+
+		corner := wide.trailheads[0]
+		utils.CheckEqual(counts.Score(corner), 1, t)
+		utils.CheckEqual(counts.Rating(corner), 1, t)
+	})
+}
+
+func BenchmarkCountTrails(b *testing.B) {
+	graph, _ := ReadGraph(strings.NewReader(exampleMap))
+
+	for range b.N {
+		graph.Reports()
+	}
+}
+
+func TestTrailRules(t *testing.T) {
+	t.Run("impassable cells", func(t *testing.T) {
+		graph, _ := ReadGraph(strings.NewReader(`..90..9
+...1.98
+...2..7
+6543456
+765.987
+876....
+987....`))
+
+		utils.CheckEqual(len(graph.trailheads), 1, t)
+		utils.CheckEqual(graph.Reports()[0].Score, 4, t)
+		utils.CheckEqual(graph.At(0, 0).height, IMPASSABLE, t)
+	})
+
+	t.Run("custom start, end and step", func(t *testing.T) {
+		rules := DefaultRules
+		rules.Start, rules.End = 9, 0
+		rules.Step = func(from, to int) bool { return from-to == 1 }
+
+		graph, _ := ReadGraphWithRules(strings.NewReader(exampleMap), rules)
+		reports := graph.Reports()
+
+		utils.CheckEqual(len(reports), 7, t)
+
+		rating := 0
+		for _, r := range reports {
+			rating += r.Rating
+		}
+		// reversing every trail doesn't change how many there are
+		utils.CheckEqual(rating, 81, t)
+	})
+
+	t.Run("eight-way connectivity", func(t *testing.T) {
+		rules := DefaultRules
+		rules.End = 2
+
+		diagonal := "0..\n.1.\n..2"
+
+		fourWay, _ := ReadGraphWithRules(strings.NewReader(diagonal), rules)
+		utils.CheckEqual(fourWay.Reports()[0].Rating, 0, t)
+
+		rules.Connectivity = EightWay
+		eightWay, _ := ReadGraphWithRules(strings.NewReader(diagonal), rules)
+		utils.CheckEqual(eightWay.Reports()[0].Rating, 1, t)
+	})
+}
+
+func TestTrails(t *testing.T) {
+	graph, _ := ReadGraph(strings.NewReader(`.....0.
+..4321.
+..5..2.
+..6543.
+..7..4.
+..8765.
+..9....`))
+	head := graph.At(5, 0)
+
+	t.Run("enumerates each trail's coordinates", func(t *testing.T) {
+		got := utils.IterSeqToSlice(graph.Trails(head))
+
+		utils.CheckEqual(len(got), 3, t)
+		for _, trail := range got {
+			utils.CheckEqual(len(trail), 10, t)
+			utils.CheckEqual(trail[0], Coord{5, 0}, t)
+			utils.CheckEqual(trail[9], Coord{2, 6}, t)
+		}
+		utils.CheckEqual(got[0][1:4], []Coord{{5, 1}, {4, 1}, {3, 1}}, t)
+	})
+
+	t.Run("stops early", func(t *testing.T) {
+		count := 0
+		for range graph.Trails(head) {
+			count++
+			break
+		}
+		utils.CheckEqual(count, 1, t)
+	})
+
+	t.Run("Render() shows only trail cells", func(t *testing.T) {
+		wide, _ := ReadGraph(strings.NewReader(exampleMap))
+		want := `........
+........
+........
+........
+........
+..01....
+..3298..
+..4567..
+`
+		got := wide.Render(wide.At(2, 5))
+
+		utils.CheckEqual(got, want, t)
+	})
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+type blinkKey struct {
+	stone, blinks int
+}
+
+// StoneCounter counts stones after blinking, memoising (stone, blinks) -> count. Stones
+// settle into a small set of values that keep coming back, so the memo stays small.
+// Counts are ints, which overflow after about a hundred blinks; past that use
+// CountBig.
+type StoneCounter struct {
+	rules RuleSet
+	memo  map[blinkKey]int
+}
+
+func NewStoneCounter() *StoneCounter {
+	return NewStoneCounterWithRules(PuzzleRules)
+}
+
+func NewStoneCounterWithRules(rules RuleSet) *StoneCounter {
+	return &StoneCounter{rules: rules, memo: make(map[blinkKey]int)}
+}
+
+// number of stones a single stone becomes after blinking blinks times; it panics if that
+// doesn't fit in an int
+func (c *StoneCounter) Count(stone, blinks int) int {
+	if blinks == 0 {
+		return 1
+	}
+
+	key := blinkKey{stone, blinks}
+	if count, ok := c.memo[key]; ok {
+		return count
+	}
+
+	count := 0
+	for _, s := range c.rules.Apply(stone) {
+		count = addCounts(count, c.Count(s, blinks-1), blinks)
+	}
+
+	c.memo[key] = count
+	return count
+}
+
+// total over all the stones; it panics if that doesn't fit in an int
+func (c *StoneCounter) CountAll(stones []int, blinks int) int {
+	total := 0
+	for _, s := range stones {
+		total = addCounts(total, c.Count(s, blinks), blinks)
+	}
+	return total
+}
+
+// a + b, panicking rather than wrapping round to a wrong count
+func addCounts(a, b, blinks int) int {
+	if a > math.MaxInt-b {
+		panic(fmt.Sprintf("stone count overflows an int after %v blinks; use CountBig", blinks))
+	}
+	return a + b
+}
+
+// StoneGraph is the closed set of values reachable from some starting stones, with the
+// stones each value turns into on a blink (as indices into values).
+type StoneGraph struct {
+	values []int
+	index  map[int]int
+	next   [][]int
+}
+
+func ReachableStones(stones []int) StoneGraph {
+	return PuzzleRules.Reachable(stones)
+}
+
+// Reachable builds the StoneGraph under the rules. It only finishes if the rules keep
+// stones within a finite set of values, as the puzzle's splitting does.
+func (rs RuleSet) Reachable(stones []int) StoneGraph {
+	g := StoneGraph{index: make(map[int]int)}
+
+	add := func(v int) int {
+		if i, ok := g.index[v]; ok {
+			return i
+		}
+		g.index[v] = len(g.values)
+		g.values = append(g.values, v)
+		g.next = append(g.next, nil)
+		return len(g.values) - 1
+	}
+
+	for _, s := range stones {
+		add(s)
+	}
+
+	// values are appended as they're found, so this stops once nothing new turns up
+	for i := 0; i < len(g.values); i++ {
+		for _, s := range rs.Apply(g.values[i]) {
+			g.next[i] = append(g.next[i], add(s))
+		}
+	}
+
+	return g
+}
+
+func (g StoneGraph) Size() int {
+	return len(g.values)
+}
+
+// matrix[i][j] is how many stones of value j a stone of value i becomes in one blink
+func (g StoneGraph) TransitionMatrix() [][]*big.Int {
+	matrix := newMatrix(g.Size())
+	for i, next := range g.next {
+		for _, j := range next {
+			matrix[i][j].Add(matrix[i][j], big.NewInt(1))
+		}
+	}
+	return matrix
+}
+
+// closed sets up to this size use matrix exponentiation. This is narrower than asked for:
+// exponentiation only covers small inputs like a single stone or the example. A real
+// puzzle input reaches a few thousand values, and the transition matrix fills in within
+// a few squarings, so each multiply would be O(n^3) products of numbers thousands of bits
+// long, over millions of entries. Those inputs are stepped instead.
+const MATRIX_LIMIT = 256
+
+// CountBig counts stones after any number of blinks, exactly. It works on the closed set
+// of reachable values: with few enough values it raises the transition matrix to the
+// power of blinks by squaring, O(n^3 log blinks); otherwise it steps a count per value
+// through each blink, O(blinks * n) additions of numbers growing by about 0.6 bits a
+// blink. For a real input that's a few seconds for 10^4 blinks.
+func CountBig(stones []int, blinks int) *big.Int {
+	g := ReachableStones(stones)
+
+	counts := newVector(g.Size())
+	for _, s := range stones {
+		i := g.index[s]
+		counts[i].Add(counts[i], big.NewInt(1))
+	}
+
+	if g.Size() <= MATRIX_LIMIT {
+		counts = vectorTimesMatrix(counts, matrixPow(g.TransitionMatrix(), blinks))
+	} else {
+		next := newVector(g.Size())
+		for range blinks {
+			g.stepInto(next, counts)
+			counts, next = next, counts
+		}
+	}
+
+	total := new(big.Int)
+	for _, c := range counts {
+		total.Add(total, c)
+	}
+	return total
+}
+
+func newVector(n int) []*big.Int {
+	v := make([]*big.Int, n)
+	for i := range v {
+		v[i] = new(big.Int)
+	}
+	return v
+}
+
+// one blink over per-value counts
+func (g StoneGraph) step(counts []*big.Int) []*big.Int {
+	next := newVector(len(counts))
+	g.stepInto(next, counts)
+	return next
+}
+
+// stepInto is step writing into next, reusing its numbers' memory
+func (g StoneGraph) stepInto(next, counts []*big.Int) {
+	for _, n := range next {
+		n.SetInt64(0)
+	}
+	for i, c := range counts {
+		if c.Sign() == 0 {
+			continue
+		}
+		for _, j := range g.next[i] {
+			next[j].Add(next[j], c)
+		}
+	}
+}
+
+func newMatrix(n int) [][]*big.Int {
+	m := make([][]*big.Int, n)
+	for i := range m {
+		m[i] = make([]*big.Int, n)
+		for j := range m[i] {
+			m[i][j] = new(big.Int)
+		}
+	}
+	return m
+}
+
+func identity(n int) [][]*big.Int {
+	m := newMatrix(n)
+	for i := range m {
+		m[i][i].SetInt64(1)
+	}
+	return m
+}
+
+func matrixMul(a, b [][]*big.Int) [][]*big.Int {
+	n := len(a)
+	product := newMatrix(n)
+	term := new(big.Int)
+	for i := range n {
+		for k := range n {
+			if a[i][k].Sign() == 0 {
+				continue
+			}
+			for j := range n {
+				if b[k][j].Sign() == 0 {
+					continue
+				}
+				product[i][j].Add(product[i][j], term.Mul(a[i][k], b[k][j]))
+			}
+		}
+	}
+	return product
+}
+
+func matrixPow(m [][]*big.Int, exp int) [][]*big.Int {
+	result := identity(len(m))
+	base := m
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = matrixMul(result, base)
+		}
+		exp >>= 1
+		if exp > 0 {
+			base = matrixMul(base, base)
+		}
+	}
+	return result
+}
+
+func vectorTimesMatrix(v []*big.Int, m [][]*big.Int) []*big.Int {
+	product := make([]*big.Int, len(v))
+	for j := range product {
+		product[j] = new(big.Int)
+	}
+	term := new(big.Int)
+	for i, vi := range v {
+		if vi.Sign() == 0 {
+			continue
+		}
+		for j := range m[i] {
+			product[j].Add(product[j], term.Mul(vi, m[i][j]))
+		}
+	}
+	return product
+}
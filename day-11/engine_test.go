@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestStoneCounter(t *testing.T) {
+	counter := NewStoneCounter()
+
+	utils.CheckEqual(counter.CountAll([]int{125, 17}, 0), 2, t)
+	utils.CheckEqual(counter.CountAll([]int{125, 17}, 6), 22, t)
+	utils.CheckEqual(counter.CountAll([]int{125, 17}, 25), 55312, t)
+	utils.CheckEqual(counter.Count(0, 1), 1, t)
+	utils.CheckEqual(counter.Count(10, 1), 2, t)
+
+	t.Run("overflow panics", func(t *testing.T) {
+		defer func() {
+			utils.CheckEqual(recover() != nil, true, t)
+		}()
+		NewStoneCounter().Count(0, 300)
+	})
+
+	t.Run("agrees with simulating every blink", func(t *testing.T) {
+		stones := []int{0, 1, 10, 99, 999}
+		for report := range PuzzleRules.Simulate(stones, 15) {
+			utils.CheckEqual(NewStoneCounter().CountAll(stones, report.Blink), report.Count, t)
+		}
+	})
+}
+
+func TestReachableStones(t *testing.T) {
+	g := ReachableStones([]int{0})
+
+	// 0 -> 1 -> 2024 -> 20 24 -> 2 0 2 4 -> ...; the set closes off quickly
+	utils.CheckEqual(g.values[:4], []int{0, 1, 2024, 20}, t)
+	utils.CheckEqual(g.Size() < MATRIX_LIMIT, true, t)
+
+	for i, next := range g.next {
+		want := PuzzleRules.Apply(g.values[i])
+		utils.CheckEqual(utils.Map(next, func(j int) int { return g.values[j] }), want, t)
+	}
+}
+
+func TestCountBig(t *testing.T) {
+	t.Run("matches the memo engine", func(t *testing.T) {
+		for _, blinks := range []int{0, 1, 6, 25, 75} {
+			want := big.NewInt(int64(NewStoneCounter().CountAll([]int{125, 17}, blinks)))
+			utils.CheckEqual(CountBig([]int{125, 17}, blinks).Cmp(want), 0, t)
+		}
+	})
+
+	t.Run("matrix and stepping agree", func(t *testing.T) {
+		stones := []int{0, 7}
+		g := ReachableStones(stones)
+
+		counts := make([]*big.Int, g.Size())
+		for i := range counts {
+			counts[i] = new(big.Int)
+		}
+		counts[g.index[0]].SetInt64(1)
+		counts[g.index[7]].SetInt64(1)
+		for range 300 {
+			counts = g.step(counts)
+		}
+		stepped := new(big.Int)
+		for _, c := range counts {
+			stepped.Add(stepped, c)
+		}
+
+		utils.CheckEqual(CountBig(stones, 300).Cmp(stepped), 0, t)
+	})
+
+	t.Run("large closed sets step instead", func(t *testing.T) {
+		stones := []int{572556, 22, 0, 528, 4679021, 1, 10725, 2790}
+		utils.CheckEqual(ReachableStones(stones).Size() > MATRIX_LIMIT, true, t)
+
+		want := big.NewInt(int64(NewStoneCounter().CountAll(stones, 75)))
+		utils.CheckEqual(CountBig(stones, 75).Cmp(want), 0, t)
+	})
+
+	t.Run("huge blink counts", func(t *testing.T) {
+		got := CountBig([]int{0}, 10000)
+
+		// about 1.5x more stones every blink
+		utils.CheckEqual(len(got.String()) > 1000, true, t)
+	})
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var ErrRuleSyntax = errors.New("invalid rule")
+
+// Rule turns a stone into any number of stones when Matches holds.
+type Rule struct {
+	Name    string
+	Matches func(stone int) bool
+	Apply   func(stone int) []int
+}
+
+// RuleSet applies the first matching rule to each stone on a blink; a stone no rule
+// matches stays as it is.
+type RuleSet struct {
+	Rules []Rule
+}
+
+// the puzzle's rules
+var PuzzleRules = RuleSet{Rules: []Rule{
+	Replace(0, 1),
+	SplitDigits(10, 2),
+	MultiplyBy(2024),
+}}
+
+func (rs RuleSet) Apply(stone int) []int {
+	for _, r := range rs.Rules {
+		if r.Matches(stone) {
+			return r.Apply(stone)
+		}
+	}
+	return []int{stone}
+}
+
+// stone becomes with
+func Replace(stone, with int) Rule {
+	return Rule{
+		Name:    fmt.Sprintf("%v -> %v", stone, with),
+		Matches: func(n int) bool { return n == stone },
+		Apply:   func(int) []int { return []int{with} },
+	}
+}
+
+// any stone is multiplied by m
+func MultiplyBy(m int) Rule {
+	return Rule{
+		Name:    fmt.Sprintf("any -> *%v", m),
+		Matches: func(int) bool { return true },
+		Apply:   func(n int) []int { return []int{n * m} },
+	}
+}
+
+// a stone whose digit count in base is a multiple of parts splits into parts stones,
+// each taking an equal share of the digits, most significant first
+func SplitDigits(base, parts int) Rule {
+	return Rule{
+		Name: fmt.Sprintf("base %v digits %%%v -> split %v", base, parts, parts),
+		Matches: func(n int) bool {
+			return digitCount(n, base)%parts == 0
+		},
+		Apply: func(n int) []int {
+			chunk := pow(base, digitCount(n, base)/parts)
+			stones := make([]int, parts)
+			for i := parts - 1; i >= 0; i-- {
+				stones[i] = n % chunk
+				n /= chunk
+			}
+			return stones
+		},
+	}
+}
+
+func digitCount(n, base int) int {
+	digits := 1
+	for n >= base {
+		n /= base
+		digits++
+	}
+	return digits
+}
+
+func pow(base, exp int) int {
+	p := 1
+	for range exp {
+		p *= base
+	}
+	return p
+}
+
+// tokens splits a rule into words, numbers and single symbols, ignoring spaces, so
+// `digits%2` and `digits % 2` read the same. A - directly before a digit is part of the
+// number.
+func tokens(s string) []string {
+	isDigit := func(i int) bool { return i < len(s) && s[i] >= '0' && s[i] <= '9' }
+	isLetter := func(i int) bool { return i < len(s) && unicode.IsLetter(rune(s[i])) }
+
+	var result []string
+	for i := 0; i < len(s); {
+		if s[i] == ' ' || s[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		i++
+		switch {
+		case isDigit(start) || (s[start] == '-' && isDigit(i)):
+			for isDigit(i) {
+				i++
+			}
+		case isLetter(start):
+			for isLetter(i) {
+				i++
+			}
+		}
+		result = append(result, s[start:i])
+	}
+	return result
+}
+
+// ParseRules reads the rule DSL, one rule per line, first match wins:
+//
+//	base 10              digit rules below this line count in base 10 (the default)
+//	0 -> 1               a stone of exactly 0 becomes 1
+//	digits %2 -> split 2 an even number of digits splits into 2
+//	any -> *2024         anything else is multiplied by 2024
+//
+// Blank lines and lines starting with # are ignored, and spaces within a rule don't
+// matter. A `split k` must follow a `digits %k` predicate, as the digits have to divide
+// evenly.
+func ParseRules(text string) (RuleSet, error) {
+	var rs RuleSet
+	base := 10
+
+	for lineNumber, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fail := func(reason string) (RuleSet, error) {
+			return RuleSet{}, fmt.Errorf("%w: line %v: %v", ErrRuleSyntax, lineNumber+1, reason)
+		}
+
+		if t := tokens(line); t[0] == "base" {
+			b, err := strconv.Atoi(strings.Join(t[1:], ""))
+			if err != nil || b < 2 {
+				return fail("base must be a number from 2")
+			}
+			base = b
+			continue
+		}
+
+		predicateText, transformText, found := strings.Cut(line, "->")
+		if !found {
+			return fail("expected <predicate> -> <transform>")
+		}
+		predicate, transform := tokens(predicateText), tokens(transformText)
+		// the number after a leading word or symbol, e.g. 2 in [digits % 2] or [* 2]
+		number := func(t []string, prefix ...string) (int, bool) {
+			if len(t) != len(prefix)+1 || !slices.Equal(t[:len(prefix)], prefix) {
+				return 0, false
+			}
+			n, err := strconv.Atoi(t[len(prefix)])
+			return n, err == nil
+		}
+
+		var rule Rule
+		switch {
+		case slices.Equal(predicate, []string{"any"}):
+			factor, ok := number(transform, "*")
+			if !ok {
+				return fail("`any` must be followed by `*<multiplier>`")
+			}
+			rule = MultiplyBy(factor)
+
+		case len(predicate) > 0 && predicate[0] == "digits":
+			k, ok := number(predicate, "digits", "%")
+			parts, splitOk := number(transform, "split")
+			if !ok || !splitOk || k != parts || parts < 2 {
+				return fail("`digits %k` must be followed by `split k`, k from 2")
+			}
+			rule = SplitDigits(base, parts)
+
+		default:
+			stone, ok := number(predicate)
+			with, withOk := number(transform)
+			if !ok || !withOk {
+				return fail("unknown predicate or transform")
+			}
+			rule = Replace(stone, with)
+		}
+
+		rs.Rules = append(rs.Rules, rule)
+	}
+
+	return rs, nil
+}
+
+// how the stones look after a blink
+type BlinkReport struct {
+	Blink int
+	Count int
+	// stone value -> number of stones with it
+	Distribution map[int]int
+}
+
+// Simulate blinks the stones under the rules, reporting after each blink. Stones with
+// the same value are counted together rather than kept individually.
+func (rs RuleSet) Simulate(stones []int, blinks int) iter.Seq[BlinkReport] {
+	return func(yield func(BlinkReport) bool) {
+		counts := make(map[int]int)
+		for _, s := range stones {
+			counts[s]++
+		}
+
+		for blink := 1; blink <= blinks; blink++ {
+			next := make(map[int]int, len(counts))
+			total := 0
+			for stone, count := range counts {
+				for _, s := range rs.Apply(stone) {
+					next[s] += count
+					total += count
+				}
+			}
+			counts = next
+
+			if !yield(BlinkReport{Blink: blink, Count: total, Distribution: counts}) {
+				return
+			}
+		}
+	}
+}
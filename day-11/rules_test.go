@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestPuzzleRules(t *testing.T) {
+	type testInput struct {
+		stone int
+		want  []int
+	}
+
+	for _, tt := range []testInput{
+		{0, []int{1}},
+		{1, []int{2024}},
+		{10, []int{1, 0}},
+		{99, []int{9, 9}},
+		{999, []int{2021976}},
+		{1000, []int{10, 0}},
+		{253000, []int{253, 0}},
+	} {
+		utils.CheckEqual(PuzzleRules.Apply(tt.stone), tt.want, t)
+	}
+}
+
+func TestRuleVariants(t *testing.T) {
+	t.Run("no rule matches", func(t *testing.T) {
+		utils.CheckEqual(RuleSet{}.Apply(5), []int{5}, t)
+		utils.CheckEqual(RuleSet{Rules: []Rule{Replace(0, 1)}}.Apply(5), []int{5}, t)
+	})
+
+	t.Run("first match wins", func(t *testing.T) {
+		rs := RuleSet{Rules: []Rule{MultiplyBy(3), Replace(0, 1)}}
+		utils.CheckEqual(rs.Apply(0), []int{0}, t)
+	})
+
+	t.Run("three-way split", func(t *testing.T) {
+		split := SplitDigits(10, 3)
+		utils.CheckEqual(split.Matches(123456), true, t)
+		utils.CheckEqual(split.Apply(123456), []int{12, 34, 56}, t)
+		utils.CheckEqual(split.Apply(100200), []int{10, 2, 0}, t)
+		utils.CheckEqual(split.Apply(123), []int{1, 2, 3}, t)
+		utils.CheckEqual(split.Matches(12), false, t)
+	})
+
+	t.Run("binary digits", func(t *testing.T) {
+		split := SplitDigits(2, 2)
+		utils.CheckEqual(split.Matches(0b1011), true, t)
+		utils.CheckEqual(split.Apply(0b1011), []int{0b10, 0b11}, t)
+		utils.CheckEqual(split.Matches(0b101), false, t)
+	})
+
+	t.Run("counter follows its rules", func(t *testing.T) {
+		rs := RuleSet{Rules: []Rule{Replace(0, 1), SplitDigits(10, 3), MultiplyBy(7)}}
+		counter := NewStoneCounterWithRules(rs)
+
+		for report := range rs.Simulate([]int{0, 5}, 20) {
+			utils.CheckEqual(counter.CountAll([]int{0, 5}, report.Blink), report.Count, t)
+		}
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	t.Run("puzzle rules", func(t *testing.T) {
+		rs, err := ParseRules(`
+			# the puzzle
+			0 -> 1
+			digits %2 -> split 2
+			any -> *2024
+		`)
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(len(rs.Rules), 3, t)
+		utils.CheckEqual(NewStoneCounterWithRules(rs).CountAll([]int{125, 17}, 25), 55312, t)
+
+		for _, stone := range []int{0, 1, 10, 99, 999, 1000, 253000} {
+			utils.CheckEqual(rs.Apply(stone), PuzzleRules.Apply(stone), t)
+		}
+	})
+
+	t.Run("base applies to later rules", func(t *testing.T) {
+		rs, err := ParseRules("base 2\ndigits %2 -> split 2\nany -> * 3")
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(rs.Apply(0b1011), []int{0b10, 0b11}, t)
+		utils.CheckEqual(rs.Apply(0b101), []int{0b1111}, t)
+	})
+
+	t.Run("spacing doesn't matter", func(t *testing.T) {
+		rs, err := ParseRules("0->1\ndigits%2 -> split2\nany->*2024")
+		utils.CheckEqual(err, nil, t)
+		for _, stone := range []int{0, 1, 10, 99, 999, 1000, 253000} {
+			utils.CheckEqual(rs.Apply(stone), PuzzleRules.Apply(stone), t)
+		}
+
+		rs, err = ParseRules("base2\ndigits % 2 -> split 2\n-1 -> 5")
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(rs.Apply(0b1011), []int{0b10, 0b11}, t)
+		utils.CheckEqual(rs.Apply(-1), []int{5}, t)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, text := range []string{
+			"digits 2 -> split 2",
+			"digits %2 -> split 2 2",
+			"any -> *2024 extra",
+			"0 => 1",
+			"digits %2 -> split 3",
+			"digits %1 -> split 1",
+			"base 1",
+			"any -> 2024",
+			"odd -> 1",
+		} {
+			_, err := ParseRules(text)
+			utils.CheckEqual(errors.Is(err, ErrRuleSyntax), true, t)
+		}
+	})
+}
+
+func TestSimulate(t *testing.T) {
+	var counts []int
+	for report := range PuzzleRules.Simulate([]int{125, 17}, 6) {
+		counts = append(counts, report.Count)
+
+		total := 0
+		for _, n := range report.Distribution {
+			total += n
+		}
+		utils.CheckEqual(total, report.Count, t)
+
+		if report.Blink == 1 {
+			utils.CheckEqual(report.Distribution, map[int]int{253000: 1, 1: 1, 7: 1}, t)
+		}
+	}
+	utils.CheckEqual(counts, []int{3, 4, 5, 9, 13, 22}, t)
+
+	t.Run("stops early", func(t *testing.T) {
+		blinks := 0
+		for range PuzzleRules.Simulate([]int{0}, 10) {
+			blinks++
+			if blinks == 2 {
+				break
+			}
+		}
+		utils.CheckEqual(blinks, 2, t)
+	})
+}
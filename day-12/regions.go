@@ -0,0 +1,229 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+
+	"iain.fyi/aoc2024/structure"
+)
+
+// a connected patch of plots growing the same crop
+type Region struct {
+	crop   string
+	plots  *structure.HashSet[*Plot]
+	coords *structure.HashSet[Coord]
+}
+
+func NewRegion() Region {
+	return Region{plots: structure.NewHashSet[*Plot](), coords: structure.NewHashSet[Coord]()}
+}
+
+func (r *Region) Crop() string {
+	return r.crop
+}
+
+func (r *Region) Area() int {
+	return r.plots.Size()
+}
+
+func (r *Region) Perimeter() int {
+	pCount := 0
+	for p := range r.plots.All() {
+		for _, adj := range p.Adjacent() {
+			if adj == nil {
+				pCount += 1
+			}
+		}
+	}
+	return pCount
+}
+
+// Sides counts straight fence sections by counting corners, since a closed fence has as
+// many sides as corners. Each plot is checked at its four corners: it's an outside corner
+// if neither neighbour along the corner is in the region, and an inside corner if both are
+// but the diagonal isn't. This counts inner fences around holes too, and two plots only
+// touching diagonally count as separate corners.
+func (r *Region) Sides() int {
+	corners := 0
+	for c := range r.coords.All() {
+		for _, d := range []Coord{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+			horizontal := r.coords.Contains(Coord{c.x + d.x, c.y})
+			vertical := r.coords.Contains(Coord{c.x, c.y + d.y})
+			diagonal := r.coords.Contains(Coord{c.x + d.x, c.y + d.y})
+
+			if (!horizontal && !vertical) || (horizontal && vertical && !diagonal) {
+				corners++
+			}
+		}
+	}
+	return corners
+}
+
+// top-left and bottom-right plots of the smallest rectangle covering the region
+func (r *Region) BoundingBox() (Coord, Coord) {
+	first := true
+	var topLeft, bottomRight Coord
+	for c := range r.coords.All() {
+		if first {
+			topLeft, bottomRight, first = c, c, false
+			continue
+		}
+		topLeft = Coord{min(topLeft.x, c.x), min(topLeft.y, c.y)}
+		bottomRight = Coord{max(bottomRight.x, c.x), max(bottomRight.y, c.y)}
+	}
+	return topLeft, bottomRight
+}
+
+// Holes counts pockets of other plots the region completely fences in, i.e. groups of
+// plots (connected up/down/left/right) that can't reach outside the region without
+// crossing it. Each hole has its own inner fence.
+func (r *Region) Holes() int {
+	topLeft, bottomRight := r.BoundingBox()
+	// one plot of margin all round, so everything outside is connected
+	origin := Coord{topLeft.x - 1, topLeft.y - 1}
+	width, height := bottomRight.x-topLeft.x+3, bottomRight.y-topLeft.y+3
+
+	inRegion := func(i int) bool {
+		return r.coords.Contains(Coord{origin.x + i%width, origin.y + i/width})
+	}
+
+	ds := structure.NewDenseUnionFind(width * height)
+	for i := range width * height {
+		if inRegion(i) {
+			continue
+		}
+		if x := i % width; x+1 < width && !inRegion(i+1) {
+			ds.Union(i, i+1)
+		}
+		if i+width < width*height && !inRegion(i+width) {
+			ds.Union(i, i+width)
+		}
+	}
+
+	outside := ds.Find(0)
+	holes := structure.NewHashSet[int]()
+	for i := range width * height {
+		if root := ds.Find(i); !inRegion(i) && root != outside {
+			holes.Add(root)
+		}
+	}
+	return holes.Size()
+}
+
+// GetRegions groups plots into regions with a disjoint-set over the grid, joining each
+// plot to its right and lower neighbour when they grow the same crop. Regions come out
+// in reading order of their first plot.
+func GetRegions(pm PlotMap) []Region {
+	index := func(c Coord) int { return c.y*pm.width + c.x }
+
+	ds := structure.NewDenseUnionFind(pm.width * pm.height)
+	for c, plot := range pm.plotByCoord {
+		if plot.right != nil {
+			ds.Union(index(c), index(Coord{c.x + 1, c.y}))
+		}
+		if plot.down != nil {
+			ds.Union(index(c), index(Coord{c.x, c.y + 1}))
+		}
+	}
+
+	var regions []Region
+	regionByRoot := make(map[int]int)
+	for y := range pm.height {
+		for x := range pm.width {
+			c := Coord{x, y}
+			plot := pm.Get(c)
+			if plot == nil {
+				continue
+			}
+
+			root := ds.Find(index(c))
+			i, ok := regionByRoot[root]
+			if !ok {
+				i = len(regions)
+				regionByRoot[root] = i
+				region := NewRegion()
+				region.crop = plot.crop
+				regions = append(regions, region)
+			}
+			regions[i].plots.Add(plot)
+			regions[i].coords.Add(c)
+		}
+	}
+
+	return regions
+}
+
+// which way a fence faces, out of the region
+type Facing int
+
+const (
+	UP Facing = iota
+	RIGHT
+	DOWN
+	LEFT
+)
+
+// A Fence is one straight side of a region. From and To are grid corners, so the fence
+// above plot (x, y) runs from (x, y) to (x+1, y).
+type Fence struct {
+	Facing   Facing
+	From, To Coord
+}
+
+// Fences lists the region's sides, one Fence per side counted by Sides. Unit fences along
+// each plot edge are merged while they continue in a straight line facing the same way.
+func (r *Region) Fences() []Fence {
+	type line struct {
+		facing Facing
+		// the fixed coordinate of the line: y for UP/DOWN, x for LEFT/RIGHT
+		at int
+	}
+	// positions along each line where a unit fence starts
+	units := make(map[line][]int)
+
+	for c := range r.coords.All() {
+		if !r.coords.Contains(Coord{c.x, c.y - 1}) {
+			units[line{UP, c.y}] = append(units[line{UP, c.y}], c.x)
+		}
+		if !r.coords.Contains(Coord{c.x + 1, c.y}) {
+			units[line{RIGHT, c.x + 1}] = append(units[line{RIGHT, c.x + 1}], c.y)
+		}
+		if !r.coords.Contains(Coord{c.x, c.y + 1}) {
+			units[line{DOWN, c.y + 1}] = append(units[line{DOWN, c.y + 1}], c.x)
+		}
+		if !r.coords.Contains(Coord{c.x - 1, c.y}) {
+			units[line{LEFT, c.x}] = append(units[line{LEFT, c.x}], c.y)
+		}
+	}
+
+	var fences []Fence
+	for l, starts := range units {
+		slices.Sort(starts)
+
+		fence := func(from, to int) Fence {
+			if l.facing == UP || l.facing == DOWN {
+				return Fence{l.facing, Coord{from, l.at}, Coord{to, l.at}}
+			}
+			return Fence{l.facing, Coord{l.at, from}, Coord{l.at, to}}
+		}
+
+		from := starts[0]
+		for i := 1; i < len(starts); i++ {
+			if starts[i] != starts[i-1]+1 {
+				fences = append(fences, fence(from, starts[i-1]+1))
+				from = starts[i]
+			}
+		}
+		fences = append(fences, fence(from, starts[len(starts)-1]+1))
+	}
+
+	// reading order, so numbering the sides is stable
+	slices.SortFunc(fences, func(a, b Fence) int {
+		return cmp.Or(
+			cmp.Compare(a.From.y, b.From.y),
+			cmp.Compare(a.From.x, b.From.x),
+			cmp.Compare(a.Facing, b.Facing),
+		)
+	})
+	return fences
+}
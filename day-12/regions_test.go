@@ -0,0 +1,108 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func regionsOf(garden string) []Region {
+	return GetRegions(ReadPlotMap(strings.NewReader(garden)))
+}
+
+func fencePrice(regions []Region, fence func(r *Region) int) int {
+	total := 0
+	for _, r := range regions {
+		total += r.Area() * fence(&r)
+	}
+	return total
+}
+
+const largerExample = `RRRRIICCFF
+RRRRIICCCF
+VVRRRCCFFF
+VVRCCCJFFF
+VVVVCJJCFE
+VVIVCCJJEE
+VVIIICJJEE
+MIIIIIJJEE
+MIIISIJEEE
+MMMISSJEEE`
+
+func TestGetRegionsUnionFind(t *testing.T) {
+	type testInput struct {
+		name          string
+		garden        string
+		regions       int
+		price, bulk   int
+		firstRegionOf string
+	}
+
+	for _, tt := range []testInput{
+		{"minimal", "AAAA\nBBCD\nBBCC\nEEEC", 5, 140, 80, "A"},
+		{"holes", "OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO", 5, 772, 436, "O"},
+		{"E shape", "EEEEE\nEXXXX\nEEEEE\nEXXXX\nEEEEE", 3, 692, 236, "E"},
+		{"diagonal touches", "AAAAAA\nAAABBA\nAAABBA\nABBAAA\nABBAAA\nAAAAAA", 3, 1184, 368, "A"},
+		{"larger", largerExample, 11, 1930, 1206, "R"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			regions := regionsOf(tt.garden)
+
+			utils.CheckEqual(len(regions), tt.regions, t)
+			utils.CheckEqual(regions[0].Crop(), tt.firstRegionOf, t)
+			utils.CheckEqual(fencePrice(regions, (*Region).Perimeter), tt.price, t)
+			utils.CheckEqual(fencePrice(regions, (*Region).Sides), tt.bulk, t)
+		})
+	}
+
+	t.Run("large map doesn't recurse", func(t *testing.T) {
+		row := strings.Repeat("A", 300)
+		garden := strings.Repeat(row+"\n", 300)
+
+		regions := regionsOf(garden)
+		utils.CheckEqual(len(regions), 1, t)
+		utils.CheckEqual(regions[0].Area(), 90000, t)
+		utils.CheckEqual(regions[0].Sides(), 4, t)
+	})
+}
+
+func TestRegionShape(t *testing.T) {
+	t.Run("single plot", func(t *testing.T) {
+		regions := regionsOf("AB\nBA")
+		utils.CheckEqual(len(regions), 4, t)
+		for _, r := range regions {
+			topLeft, bottomRight := r.BoundingBox()
+			utils.CheckEqual(topLeft, bottomRight, t)
+			utils.CheckEqual(r.Area(), 1, t)
+			utils.CheckEqual(r.Perimeter(), 4, t)
+			utils.CheckEqual(r.Sides(), 4, t)
+			utils.CheckEqual(r.Holes(), 0, t)
+		}
+	})
+
+	t.Run("region with holes", func(t *testing.T) {
+		o := regionsOf("OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO")[0]
+		topLeft, bottomRight := o.BoundingBox()
+
+		utils.CheckEqual(o.Area(), 21, t)
+		utils.CheckEqual(o.Perimeter(), 36, t)
+		utils.CheckEqual(o.Sides(), 20, t)
+		utils.CheckEqual(o.Holes(), 4, t)
+		utils.CheckEqual(topLeft, Coord{0, 0}, t)
+		utils.CheckEqual(bottomRight, Coord{4, 4}, t)
+	})
+
+	t.Run("holes touching diagonally are separate", func(t *testing.T) {
+		a := regionsOf("AAAAAA\nAAABBA\nAAABBA\nABBAAA\nABBAAA\nAAAAAA")[0]
+		utils.CheckEqual(a.Sides(), 12, t)
+		utils.CheckEqual(a.Holes(), 2, t)
+	})
+
+	t.Run("pocket open to the outside isn't a hole", func(t *testing.T) {
+		c := regionsOf("CCC\nC.C\nC..")[0]
+		utils.CheckEqual(c.Crop(), "C", t)
+		utils.CheckEqual(c.Holes(), 0, t)
+		utils.CheckEqual(c.Sides(), 8, t)
+	})
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+)
+
+// RegionColour picks a fill for the i'th region, stepping the hue by the golden ratio so
+// regions close together in reading order get far-apart hues.
+func RegionColour(i int) color.RGBA {
+	hue := math.Mod(float64(i)*0.618033988749895, 1) * 6
+	const saturation, value = 0.45, 0.95
+
+	c := value * saturation
+	x := c * (1 - math.Abs(math.Mod(hue, 2)-1))
+	m := value - c
+
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	channel := func(v float64) uint8 { return uint8(math.Round((v + m) * 255)) }
+	return color.RGBA{channel(r), channel(g), channel(b), 255}
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// region index of every plot
+func regionIndex(regions []Region) map[Coord]int {
+	index := make(map[Coord]int)
+	for i, r := range regions {
+		for c := range r.coords.All() {
+			index[c] = i
+		}
+	}
+	return index
+}
+
+// where a fence's side number goes: its midpoint, nudged into the region, in cells
+func labelPosition(f Fence) (float64, float64) {
+	x, y := float64(f.From.x+f.To.x)/2, float64(f.From.y+f.To.y)/2
+	const nudge = 0.25
+	switch f.Facing {
+	case UP:
+		y += nudge
+	case RIGHT:
+		x -= nudge
+	case DOWN:
+		y -= nudge
+	case LEFT:
+		x += nudge
+	}
+	return x, y
+}
+
+// RenderSVG draws the garden with each region filled in its own colour, fences along
+// every region boundary, and each region's sides numbered from 1 in reading order.
+func RenderSVG(w io.Writer, pm PlotMap, cellSize int) error {
+	regions := GetRegions(pm)
+	out := bufio.NewWriter(w)
+	size := float64(cellSize)
+
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v">`+"\n",
+		pm.width*cellSize, pm.height*cellSize)
+
+	for i, r := range regions {
+		fill := hex(RegionColour(i))
+		for c := range r.coords.All() {
+			fmt.Fprintf(out, `<rect x="%v" y="%v" width="%v" height="%v" fill="%v"><title>%v</title></rect>`+"\n",
+				c.x*cellSize, c.y*cellSize, cellSize, cellSize, fill, r.crop)
+		}
+	}
+
+	for _, r := range regions {
+		for n, f := range r.Fences() {
+			fmt.Fprintf(out, `<line x1="%v" y1="%v" x2="%v" y2="%v" stroke="black" stroke-width="2"/>`+"\n",
+				f.From.x*cellSize, f.From.y*cellSize, f.To.x*cellSize, f.To.y*cellSize)
+
+			x, y := labelPosition(f)
+			fmt.Fprintf(out, `<text x="%v" y="%v" font-size="%v" text-anchor="middle" dominant-baseline="central">%v</text>`+"\n",
+				x*size, y*size, size/3, n+1)
+		}
+	}
+
+	fmt.Fprintln(out, "</svg>")
+	return out.Flush()
+}
+
+// 3x5 digits for RenderPNG, a row per string
+var digitGlyphs = [10][5]string{
+	{"###", "#.#", "#.#", "#.#", "###"},
+	{".#.", "##.", ".#.", ".#.", "###"},
+	{"###", "..#", "###", "#..", "###"},
+	{"###", "..#", "###", "..#", "###"},
+	{"#.#", "#.#", "###", "..#", "..#"},
+	{"###", "#..", "###", "..#", "###"},
+	{"###", "#..", "###", "#.#", "###"},
+	{"###", "..#", "..#", "..#", "..#"},
+	{"###", "#.#", "###", "#.#", "###"},
+	{"###", "#.#", "###", "..#", "###"},
+}
+
+// RenderPNG draws the same picture as RenderSVG as a bitmap. Side numbers use a built-in
+// 3x5 pixel font, scaled with the cell size.
+func RenderPNG(w io.Writer, pm PlotMap, cellSize int) error {
+	regions := GetRegions(pm)
+	img := image.NewRGBA(image.Rect(0, 0, pm.width*cellSize+1, pm.height*cellSize+1))
+
+	fill := func(x0, y0, x1, y1 int, c color.Color) {
+		for y := max(y0, 0); y < min(y1, img.Rect.Max.Y); y++ {
+			for x := max(x0, 0); x < min(x1, img.Rect.Max.X); x++ {
+				img.Set(x, y, c)
+			}
+		}
+	}
+
+	for i, r := range regions {
+		colour := RegionColour(i)
+		for c := range r.coords.All() {
+			fill(c.x*cellSize, c.y*cellSize, (c.x+1)*cellSize, (c.y+1)*cellSize, colour)
+		}
+	}
+
+	black := color.RGBA{0, 0, 0, 255}
+	scale := max(1, cellSize/16)
+	for _, r := range regions {
+		for n, f := range r.Fences() {
+			x0, y0 := f.From.x*cellSize, f.From.y*cellSize
+			x1, y1 := f.To.x*cellSize, f.To.y*cellSize
+			fill(x0-scale/2, y0-scale/2, x1+scale-scale/2, y1+scale-scale/2, black)
+
+			label := strconv.Itoa(n + 1)
+			x, y := labelPosition(f)
+			left := int(x*float64(cellSize)) - (len(label)*4-1)*scale/2
+			top := int(y*float64(cellSize)) - 5*scale/2
+			for i, d := range label {
+				for row, line := range digitGlyphs[d-'0'] {
+					for col, bit := range line {
+						if bit == '#' {
+							px, py := left+(i*4+col)*scale, top+row*scale
+							fill(px, py, px+scale, py+scale, black)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// RenderANSI prints the garden to a terminal, each plot's crop on its region's colour.
+func RenderANSI(w io.Writer, pm PlotMap) error {
+	index := regionIndex(GetRegions(pm))
+	out := bufio.NewWriter(w)
+
+	for y := range pm.height {
+		for x := range pm.width {
+			plot := pm.Get(Coord{x, y})
+			if plot == nil {
+				fmt.Fprint(out, " ")
+				continue
+			}
+			c := RegionColour(index[Coord{x, y}])
+			fmt.Fprintf(out, "\x1b[48;2;%v;%v;%vm\x1b[30m%v", c.R, c.G, c.B, plot.crop)
+		}
+		fmt.Fprint(out, "\x1b[0m\n")
+	}
+
+	return out.Flush()
+}
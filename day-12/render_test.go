@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestFences(t *testing.T) {
+	t.Run("single plot", func(t *testing.T) {
+		r := regionsOf("A")[0]
+		utils.CheckEqual(r.Fences(), []Fence{
+			{UP, Coord{0, 0}, Coord{1, 0}},
+			{LEFT, Coord{0, 0}, Coord{0, 1}},
+			{RIGHT, Coord{1, 0}, Coord{1, 1}},
+			{DOWN, Coord{0, 1}, Coord{1, 1}},
+		}, t)
+	})
+
+	t.Run("one fence per side", func(t *testing.T) {
+		for _, garden := range []string{
+			"AAAA\nBBCD\nBBCC\nEEEC",
+			"OOOOO\nOXOXO\nOOOOO\nOXOXO\nOOOOO",
+			"EEEEE\nEXXXX\nEEEEE\nEXXXX\nEEEEE",
+			"AAAAAA\nAAABBA\nAAABBA\nABBAAA\nABBAAA\nAAAAAA",
+			largerExample,
+		} {
+			for _, r := range regionsOf(garden) {
+				utils.CheckEqual(len(r.Fences()), r.Sides(), t)
+			}
+		}
+	})
+
+	t.Run("fences meeting at a diagonal stay apart", func(t *testing.T) {
+		a := regionsOf("AAAAAA\nAAABBA\nAAABBA\nABBAAA\nABBAAA\nAAAAAA")[0]
+
+		var downAt3 []Fence
+		for _, f := range a.Fences() {
+			if f.Facing == DOWN && f.From.y == 3 {
+				downAt3 = append(downAt3, f)
+			}
+		}
+		utils.CheckEqual(downAt3, []Fence{{DOWN, Coord{1, 3}, Coord{3, 3}}}, t)
+	})
+}
+
+func TestRegionColour(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := range 50 {
+		seen[hex(RegionColour(i))] = true
+	}
+	utils.CheckEqual(len(seen), 50, t)
+}
+
+func TestRenderers(t *testing.T) {
+	pm := ReadPlotMap(strings.NewReader("AAAA\nBBCD\nBBCC\nEEEC"))
+	regions := GetRegions(pm)
+
+	t.Run("SVG", func(t *testing.T) {
+		var buf bytes.Buffer
+		utils.CheckEqual(RenderSVG(&buf, pm, 10), nil, t)
+		svg := buf.String()
+
+		sides := 0
+		for _, r := range regions {
+			sides += r.Sides()
+		}
+		utils.CheckEqual(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="40" height="40">`), true, t)
+		utils.CheckEqual(strings.Count(svg, "<rect "), 16, t)
+		utils.CheckEqual(strings.Count(svg, "<line "), sides, t)
+		utils.CheckEqual(strings.Count(svg, "<text "), sides, t)
+		utils.CheckEqual(strings.Contains(svg, hex(RegionColour(len(regions)-1))), true, t)
+	})
+
+	t.Run("PNG", func(t *testing.T) {
+		var buf bytes.Buffer
+		utils.CheckEqual(RenderPNG(&buf, pm, 16), nil, t)
+
+		img, err := png.Decode(&buf)
+		utils.CheckEqual(err, nil, t)
+		utils.CheckEqual(img.Bounds().Dx(), 65, t)
+		utils.CheckEqual(img.Bounds().Dy(), 65, t)
+
+		// a corner of a plot away from fences and labels shows its region's colour
+		for i, r := range regions {
+			for c := range r.coords.All() {
+				got := img.At(c.x*16+3, c.y*16+3)
+				utils.CheckEqual(got, color.Color(RegionColour(i)), t)
+			}
+		}
+	})
+
+	t.Run("ANSI", func(t *testing.T) {
+		var buf bytes.Buffer
+		utils.CheckEqual(RenderANSI(&buf, pm), nil, t)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		utils.CheckEqual(len(lines), 4, t)
+		utils.CheckEqual(strings.Count(lines[0], "\x1b[48;2;"), 4, t)
+		utils.CheckEqual(strings.HasSuffix(lines[0], "A\x1b[0m"), true, t)
+
+		c := RegionColour(1)
+		utils.CheckEqual(strings.HasPrefix(lines[1], fmt.Sprintf("\x1b[48;2;%v;%v;%vm\x1b[30mB", c.R, c.G, c.B)), true, t)
+	})
+}
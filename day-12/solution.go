@@ -3,14 +3,13 @@ package main
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"iter"
 	"maps"
 	"os"
-	"slices"
 	"strings"
-
-	"iain.fyi/aoc2024/utils"
 )
 
 var ErrInputFile = errors.New("cannot open input file")
@@ -39,7 +38,8 @@ func (p *Plot) Adjacent() []*Plot {
 }
 
 type PlotMap struct {
-	plotByCoord map[Coord]*Plot
+	plotByCoord   map[Coord]*Plot
+	width, height int
 }
 
 func (pm *PlotMap) Put(coord Coord, point *Plot) {
@@ -89,7 +89,11 @@ func GetInput(filename string) (*Input, error) {
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return &Input{ReadPlotMap(file)}, nil
+}
+
+func ReadPlotMap(r io.Reader) PlotMap {
+	scanner := bufio.NewScanner(r)
 
 	plotMap := PlotMap{
 		plotByCoord: make(map[Coord]*Plot),
@@ -113,7 +117,7 @@ func GetInput(filename string) (*Input, error) {
 			if y > 0 {
 				upCoord := Coord{x, y - 1}
 				up := plotMap.Get(upCoord)
-				if up.crop == currentPlot.crop {
+				if up != nil && up.crop == currentPlot.crop {
 					currentPlot.up = up
 					up.down = &currentPlot
 				}
@@ -121,150 +125,44 @@ func GetInput(filename string) (*Input, error) {
 			}
 			plotMap.Put(coord, &currentPlot)
 		}
+		plotMap.width = max(plotMap.width, len(split))
 		y += 1
 	}
+	plotMap.height = y
 
-	return &Input{plotMap}, nil
-}
-
-type Set[T comparable] struct {
-	data map[T]bool
-}
-
-func (s *Set[T]) Put(t T) {
-	s.data[t] = true
-}
-
-func (s *Set[T]) PutAll(t []T) {
-	for _, tt := range t {
-		s.Put(tt)
-	}
-}
-
-func (s *Set[T]) Exists(t T) bool {
-	_, ok := s.data[t]
-	return ok
-}
-
-func NewSet[T comparable]() Set[T] {
-	data := make(map[T]bool)
-	return Set[T]{data}
-}
-
-type Region struct {
-	plots Set[*Plot]
-}
-
-func (r *Region) Area() int {
-	return len(r.plots.data)
-}
-
-func (r *Region) Perimeter() int {
-	pCount := 0
-	for p, _ := range r.plots.data {
-		adjacent := p.Adjacent()
-		for _, adj := range adjacent {
-			if adj == nil {
-				pCount += 1
-			}
-		}
-	}
-	return pCount
-}
-
-func SidesDiff(initial int, new int) int {
-	return utils.Abs(new - initial)
+	return plotMap
 }
 
-// an "edge" -- combination of plot and dir
-type Walked struct {
-	plot *Plot
-	dir  int
-}
-
-// walk around edges of region, counting distinct "sides"
-func (r *Region) Sides() int {
-	// track edges walked
-	edgesWalked := NewSet[Walked]()
-
-	start := utils.IterSeqToSlice(maps.Keys(r.plots.data))[0]
-	adjacent := start.Adjacent()
+func main() {
+	svgFile := flag.String("svg", "", "draw the garden's regions and numbered sides to this SVG file")
+	pngFile := flag.String("png", "", "draw the garden's regions and numbered sides to this PNG file")
+	ansi := flag.Bool("ansi", false, "print the garden's regions in colour to the terminal")
+	cellSize := flag.Int("cell", 32, "size of a plot in pixels when drawing")
+	flag.Parse()
 
-	if slices.Equal(adjacent, []*Plot{nil, nil, nil, nil}) {
-		// single block
-		return 4
-	}
+	input, _ := GetInput("input.txt")
 
-	sides := 1
-	plotNotNil := func(p *Plot) bool { return p != nil }
-	currentDir, current := utils.First(adjacent, plotNotNil)
-
-	edgesWalked.Put(Walked{start, currentDir})
-
-	// until we reach start again, and point in the init direction
-	for {
-		adjacent = current.Adjacent()
-		// keep going in same direction if we can
-		current = adjacent[currentDir]
-		// otherwise, figure out next step
-		if current == nil {
-			prevDir := currentDir
-			currentDir, current = utils.First(adjacent, plotNotNil)
-			diff := SidesDiff(prevDir, currentDir)
-			sides += diff
+	draw := func(filename string, render func(io.Writer, PlotMap, int) error) {
+		file, err := os.Create(filename)
+		if err != nil {
+			fmt.Println(err)
+			return
 		}
-
-		if edgesWalked.Exists(Walked{current, currentDir}) {
-			break
+		defer file.Close()
+		if err := render(file, input.plotMap, *cellSize); err != nil {
+			fmt.Println(err)
 		}
-
-		edgesWalked.Put(Walked{current, currentDir})
 	}
-
-	return sides - 1
-}
-
-func NewRegion() Region {
-	return Region{plots: NewSet[*Plot]()}
-}
-
-func GetRegions(pm PlotMap) []Region {
-	seen := NewSet[*Plot]()
-	it := pm.GetPlotsIter()
-
-	var walk func(plot *Plot, region *Region)
-	walk = func(plot *Plot, region *Region) {
-		if !seen.Exists(plot) {
-			seen.Put(plot)
-			region.plots.Put(plot)
-
-			for _, p := range []*Plot{plot.up, plot.right, plot.down, plot.left} {
-				if p != nil && !seen.Exists(p) && !region.plots.Exists(p) {
-					walk(p, region)
-					region.plots.Put(p)
-				}
-			}
-		}
+	if *svgFile != "" {
+		draw(*svgFile, RenderSVG)
 	}
-
-	var regions []Region
-	for plot := range it {
-		region := NewRegion()
-		walk(plot, &region)
-		// if we populated region at all, we saw a new region
-		if len(region.plots.data) > 0 {
-			plots := utils.IterSeqToSlice(maps.Keys(region.plots.data))
-			region.plots.PutAll(plots)
-			regions = append(regions, region)
-		}
-		region = NewRegion()
+	if *pngFile != "" {
+		draw(*pngFile, RenderPNG)
+	}
+	if *ansi {
+		RenderANSI(os.Stdout, input.plotMap)
 	}
 
-	return regions
-}
-
-func main() {
-	input, _ := GetInput("input.txt")
 	p1Result := Part1(input)
 	fmt.Printf("Part 1: got %v\n", p1Result)
 
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"maps"
 	"testing"
 
 	"iain.fyi/aoc2024/utils"
@@ -70,14 +69,14 @@ func TestRegion(t *testing.T) {
 	crop := "A"
 	filter := func(r Region) bool {
 		// this feels dirty
-		for k := range maps.Keys(r.plots.data) {
+		for k := range r.plots.All() {
 			return k.crop == crop
 		}
 		return false
 	}
 	regionA := utils.Filter(got, filter)[0]
 	t.Run("Area()", func(t *testing.T) {
-		utils.CheckEqual(len(regionA.plots.data), 4, t)
+		utils.CheckEqual(regionA.plots.Size(), 4, t)
 		utils.CheckEqual(regionA.Area(), 4, t)
 	})
 
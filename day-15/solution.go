@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"iain.fyi/aoc2024/structure"
 )
 
 var ErrInputFile = errors.New("cannot open input file")
@@ -191,7 +193,7 @@ func (g *Grid) Up() {
 
 type Input struct {
 	grid  Grid
-	moves *[]string
+	moves *structure.Deque[string]
 }
 
 func (i *Input) Run() {
@@ -214,13 +216,10 @@ func (i *Input) Run() {
 
 func (i *Input) PopMove() string {
 	const EMPTY = ""
-	moves := *i.moves
-	if len(moves) == 0 {
+	move, ok := i.moves.PopFront()
+	if !ok {
 		return EMPTY
 	}
-	move := moves[0]
-	poppedMoves := moves[1:]
-	i.moves = &poppedMoves
 	return move
 }
 
@@ -258,13 +257,15 @@ func GetInput(filename string) (*Input, error) {
 		}
 	}
 
-	var moves []string
+	moves := structure.NewDeque[string](0)
 	for scanner.Scan() {
 		line := strings.Split(scanner.Text(), "")
 		if len(line) == 0 {
 			break
 		}
-		moves = append(moves, line...)
+		for _, move := range line {
+			moves.PushBack(move)
+		}
 	}
 
 	input := Input{
@@ -273,7 +274,7 @@ func GetInput(filename string) (*Input, error) {
 			maxX:  maxX,
 			maxY:  y,
 		},
-		moves: &moves,
+		moves: moves,
 	}
 
 	return &input, nil
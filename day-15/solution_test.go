@@ -3,6 +3,7 @@ package main
 import (
 	"testing"
 
+	"iain.fyi/aoc2024/structure"
 	"iain.fyi/aoc2024/utils"
 )
 
@@ -10,7 +11,7 @@ func TestGetInput(t *testing.T) {
 	got, _ := GetInput("input_example.txt")
 
 	utils.CheckEqual(len(got.grid.cgMap), 100, t)
-	utils.CheckEqual(len(*got.moves), 700, t)
+	utils.CheckEqual(got.moves.Len(), 700, t)
 	utils.CheckEqual(got.grid.maxX, 10, t)
 	utils.CheckEqual(got.grid.maxY, 10, t)
 }
@@ -22,9 +23,11 @@ func TestInput(t *testing.T) {
 	})
 
 	t.Run("PopMove() gets next move", func(t *testing.T) {
-		input := Input{
-			moves: &[]string{"^", "v", ">"},
+		moves := structure.NewDeque[string](3)
+		for _, move := range []string{"^", "v", ">"} {
+			moves.PushBack(move)
 		}
+		input := Input{moves: moves}
 
 		want := []string{"^", "v", ">", ""}
 
@@ -293,7 +293,7 @@ func Part2(input *Input) int {
 	tiles.AddAll(current, end)
 	// toVisit := GetVerticesPointingTo(current, distances)
 
-	var toVisit []*Vertex
+	toVisit := structure.NewDeque[*Vertex](0)
 	for current != end {
 		inbound := GetVerticesPointingTo(current, distances)
 		for _, vert := range inbound {
@@ -302,17 +302,17 @@ func Part2(input *Input) int {
 			// if `incomingVert` was in a best path, it cost 1 or 1001 to get to current
 			if dist == currentCost || dist == currentCost-1 || dist == currentCost-1001 {
 				tiles.Add(vert.from)
-				toVisit = append(toVisit, vert)
+				toVisit.PushBack(vert)
 			}
 		}
 
-		if len(toVisit) == 0 {
+		next, ok := toVisit.PopFront()
+		if !ok {
 			break
 		}
 
-		current = toVisit[0].from
-		currentCost = distances[toVisit[0]]
-		toVisit = toVisit[1:]
+		current = next.from
+		currentCost = distances[next]
 	}
 
 	return tiles.Size()
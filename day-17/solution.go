@@ -166,11 +166,7 @@ func (i *Input) Clone() *Input {
 		B:                i.debugger.State.B,
 		C:                i.debugger.State.C,
 		InstructionIndex: i.debugger.State.InstructionIndex,
-		Output:           structure.NewList[int](),
-	}
-
-	for _, i := range i.debugger.State.Output.AsSlice() {
-		state.Output.Add(i)
+		Output:           i.debugger.State.Output.Clone(),
 	}
 
 	debugger := Debugger{
@@ -0,0 +1,227 @@
+package structure
+
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+	"strings"
+)
+
+// BitSet is a set of non-negative ints, one bit each, growing as needed. For dense small
+// ints it's far smaller than a HashSet and needs no hashing.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet makes a set with room for 0..n-1 before it has to grow.
+func NewBitSet(n int) *BitSet {
+	return &BitSet{words: make([]uint64, (max(n, 0)+63)/64)}
+}
+
+func checkBit(i int) {
+	if i < 0 {
+		panic(fmt.Sprintf("bit index %v is negative", i))
+	}
+}
+
+func (b *BitSet) Set(i int) {
+	checkBit(i)
+	w := i / 64
+	if w >= len(b.words) {
+		b.words = append(b.words, make([]uint64, w+1-len(b.words))...)
+	}
+	b.words[w] |= 1 << (i % 64)
+}
+
+func (b *BitSet) Clear(i int) {
+	checkBit(i)
+	if w := i / 64; w < len(b.words) {
+		b.words[w] &^= 1 << (i % 64)
+	}
+}
+
+func (b *BitSet) Test(i int) bool {
+	checkBit(i)
+	w := i / 64
+	return w < len(b.words) && b.words[w]&(1<<(i%64)) != 0
+}
+
+// Reset clears every bit, keeping the memory for reuse.
+func (b *BitSet) Reset() {
+	clear(b.words)
+}
+
+// number of bits set
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// NextSet is the first set bit at i or after; ok is false if there's none.
+func (b *BitSet) NextSet(i int) (next int, ok bool) {
+	checkBit(i)
+	w := i / 64
+	if w >= len(b.words) {
+		return 0, false
+	}
+	// ignore bits below i in its word
+	word := b.words[w] >> (i % 64)
+	if word != 0 {
+		return i + bits.TrailingZeros64(word), true
+	}
+	for w++; w < len(b.words); w++ {
+		if b.words[w] != 0 {
+			return w*64 + bits.TrailingZeros64(b.words[w]), true
+		}
+	}
+	return 0, false
+}
+
+// All iterates the set bits in ascending order.
+func (b *BitSet) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func (b *BitSet) Clone() *BitSet {
+	return &BitSet{words: append([]uint64{}, b.words...)}
+}
+
+// combine applies op word by word, treating missing words as zero
+func (b *BitSet) combine(o *BitSet, op func(a, b uint64) uint64) *BitSet {
+	result := &BitSet{words: make([]uint64, max(len(b.words), len(o.words)))}
+	for i := range result.words {
+		var x, y uint64
+		if i < len(b.words) {
+			x = b.words[i]
+		}
+		if i < len(o.words) {
+			y = o.words[i]
+		}
+		result.words[i] = op(x, y)
+	}
+	return result
+}
+
+// bits set in both
+func (b *BitSet) And(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x & y })
+}
+
+// bits set in either
+func (b *BitSet) Or(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x | y })
+}
+
+// bits set in exactly one
+func (b *BitSet) Xor(o *BitSet) *BitSet {
+	return b.combine(o, func(x, y uint64) uint64 { return x ^ y })
+}
+
+// whether the same bits are set, however much room each has
+func (b *BitSet) Equal(o *BitSet) bool {
+	return b.Xor(o).Count() == 0
+}
+
+// e.g. {1, 5, 64}
+func (b *BitSet) String() string {
+	var elements []string
+	for i := range b.All() {
+		elements = append(elements, fmt.Sprint(i))
+	}
+	return "{" + strings.Join(elements, ", ") + "}"
+}
+
+// BitGrid is a width x height grid of booleans, a bit per cell, for tracking things like
+// visited cells without a map.
+type BitGrid struct {
+	width, height int
+	bits          *BitSet
+}
+
+func NewBitGrid(width, height int) *BitGrid {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("bad grid size %vx%v", width, height))
+	}
+	return &BitGrid{width: width, height: height, bits: NewBitSet(width * height)}
+}
+
+func (g *BitGrid) Width() int {
+	return g.width
+}
+
+func (g *BitGrid) Height() int {
+	return g.height
+}
+
+func (g *BitGrid) InBounds(x, y int) bool {
+	return x >= 0 && x < g.width && y >= 0 && y < g.height
+}
+
+// bit index of a cell, panicking if it's off the grid
+func (g *BitGrid) index(x, y int) int {
+	if !g.InBounds(x, y) {
+		panic(fmt.Sprintf("cell (%v, %v) outside %vx%v grid", x, y, g.width, g.height))
+	}
+	return y*g.width + x
+}
+
+// Set marks a cell; it panics if the cell is off the grid.
+func (g *BitGrid) Set(x, y int) {
+	g.bits.Set(g.index(x, y))
+}
+
+// Clear unmarks a cell; it panics if the cell is off the grid.
+func (g *BitGrid) Clear(x, y int) {
+	g.bits.Clear(g.index(x, y))
+}
+
+// Test is whether a cell is marked; cells off the grid never are.
+func (g *BitGrid) Test(x, y int) bool {
+	return g.InBounds(x, y) && g.bits.Test(y*g.width+x)
+}
+
+// Reset unmarks every cell, keeping the memory for reuse.
+func (g *BitGrid) Reset() {
+	g.bits.Reset()
+}
+
+// number of cells marked
+func (g *BitGrid) Count() int {
+	return g.bits.Count()
+}
+
+// All iterates the marked cells in reading order.
+func (g *BitGrid) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i := range g.bits.All() {
+			if !yield(i%g.width, i/g.width) {
+				return
+			}
+		}
+	}
+}
+
+// the grid with # for marked cells and . otherwise, a line per row
+func (g *BitGrid) String() string {
+	var sb strings.Builder
+	for y := range g.height {
+		for x := range g.width {
+			if g.Test(x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,187 @@
+package structure
+
+import (
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func bitSetOf(bits ...int) *BitSet {
+	b := NewBitSet(0)
+	for _, i := range bits {
+		b.Set(i)
+	}
+	return b
+}
+
+func TestBitSet(t *testing.T) {
+	t.Run("Set(), Clear() and Test()", func(t *testing.T) {
+		b := NewBitSet(10)
+		b.Set(3)
+		b.Set(200)
+		b.Clear(3)
+		b.Clear(1000)
+
+		utils.CheckEqual(b.Test(3), false, t)
+		utils.CheckEqual(b.Test(200), true, t)
+		utils.CheckEqual(b.Test(5000), false, t)
+		utils.CheckEqual(b.Count(), 1, t)
+	})
+
+	t.Run("negative index panics", func(t *testing.T) {
+		defer func() {
+			utils.CheckEqual(recover() != nil, true, t)
+		}()
+		NewBitSet(1).Set(-1)
+	})
+
+	t.Run("NextSet()", func(t *testing.T) {
+		b := bitSetOf(0, 63, 64, 300)
+
+		var got []int
+		for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+			got = append(got, i)
+		}
+		utils.CheckEqual(got, []int{0, 63, 64, 300}, t)
+
+		next, ok := b.NextSet(65)
+		utils.CheckEqual(next, 300, t)
+		utils.CheckEqual(ok, true, t)
+
+		_, ok = b.NextSet(301)
+		utils.CheckEqual(ok, false, t)
+		_, ok = b.NextSet(10000)
+		utils.CheckEqual(ok, false, t)
+	})
+
+	t.Run("And(), Or() and Xor()", func(t *testing.T) {
+		a := bitSetOf(1, 2, 3, 100)
+		b := bitSetOf(2, 3, 4)
+
+		utils.CheckEqual(a.And(b).String(), "{2, 3}", t)
+		utils.CheckEqual(a.Or(b).String(), "{1, 2, 3, 4, 100}", t)
+		utils.CheckEqual(a.Xor(b).String(), "{1, 4, 100}", t)
+		utils.CheckEqual(a.String(), "{1, 2, 3, 100}", t)
+	})
+
+	t.Run("Equal() ignores spare room", func(t *testing.T) {
+		a := bitSetOf(1, 2)
+		b := NewBitSet(1000)
+		b.Set(1)
+		b.Set(2)
+
+		utils.CheckEqual(a.Equal(b), true, t)
+		b.Set(999)
+		utils.CheckEqual(a.Equal(b), false, t)
+	})
+
+	t.Run("Clone() and Reset()", func(t *testing.T) {
+		a := bitSetOf(5)
+		clone := a.Clone()
+		a.Reset()
+
+		utils.CheckEqual(a.Count(), 0, t)
+		utils.CheckEqual(clone.Test(5), true, t)
+	})
+
+	t.Run("All() matches a HashSet", func(t *testing.T) {
+		b := NewBitSet(0)
+		h := NewHashSet[int]()
+		for i := range 500 {
+			if i%7 == 0 || i%11 == 3 {
+				b.Set(i)
+				h.Add(i)
+			}
+		}
+
+		utils.CheckEqual(utils.IterSeqToSlice(b.All()), h.Sorted(func(a, b int) int { return a - b }), t)
+	})
+}
+
+func TestBitGrid(t *testing.T) {
+	t.Run("cells", func(t *testing.T) {
+		g := NewBitGrid(3, 2)
+		g.Set(0, 0)
+		g.Set(2, 1)
+		g.Set(1, 1)
+		g.Clear(1, 1)
+
+		utils.CheckEqual(g.Test(0, 0), true, t)
+		utils.CheckEqual(g.Test(1, 1), false, t)
+		utils.CheckEqual(g.Test(3, 0), false, t)
+		utils.CheckEqual(g.Test(-1, 0), false, t)
+		utils.CheckEqual(g.Count(), 2, t)
+		utils.CheckEqual(g.String(), "#..\n..#\n", t)
+		utils.CheckEqual(g.Width(), 3, t)
+		utils.CheckEqual(g.Height(), 2, t)
+	})
+
+	t.Run("Set() off the grid panics", func(t *testing.T) {
+		defer func() {
+			utils.CheckEqual(recover() != nil, true, t)
+		}()
+		// would wrap onto the next row without the bounds check
+		NewBitGrid(3, 2).Set(3, 0)
+	})
+
+	t.Run("All()", func(t *testing.T) {
+		g := NewBitGrid(4, 4)
+		g.Set(3, 2)
+		g.Set(1, 0)
+
+		var got [][2]int
+		for x, y := range g.All() {
+			got = append(got, [2]int{x, y})
+		}
+		utils.CheckEqual(got, [][2]int{{1, 0}, {3, 2}}, t)
+
+		g.Reset()
+		utils.CheckEqual(g.Count(), 0, t)
+	})
+}
+
+// visits pseudo-random cells, twice as many visits as cells so most are seen and many
+// revisited, counting first visits
+func benchmarkGridWalk(size int, visit func(x, y int) bool) int {
+	state := uint64(1)
+	count := 0
+	for range size * size * 2 {
+		state = state*6364136223846793005 + 1442695040888963407
+		if !visit(int(state>>40)%size, int(state>>20)%size) {
+			count++
+		}
+	}
+	return count
+}
+
+const benchmarkGridSize = 130
+
+func BenchmarkBitGrid(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		seen := NewBitGrid(benchmarkGridSize, benchmarkGridSize)
+		benchmarkGridWalk(benchmarkGridSize, func(x, y int) bool {
+			visited := seen.Test(x, y)
+			seen.Set(x, y)
+			return visited
+		})
+	}
+}
+
+type benchmarkPoint struct {
+	x, y int
+}
+
+// what the grid days do now
+func BenchmarkMapSeen(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		seen := make(map[benchmarkPoint]bool)
+		benchmarkGridWalk(benchmarkGridSize, func(x, y int) bool {
+			p := benchmarkPoint{x, y}
+			visited := seen[p]
+			seen[p] = true
+			return visited
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package structure
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Deque is a double-ended queue on a growable ring buffer. The buffer only grows, so once
+// it's big enough pushes and pops don't allocate.
+type Deque[T any] struct {
+	buf []T
+	// index of the front element in buf
+	head int
+	len  int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{buf: make([]T, max(capacity, 1))}
+}
+
+func (d *Deque[T]) Len() int {
+	return d.len
+}
+
+// buf index of the i'th element from the front
+func (d *Deque[T]) slot(i int) int {
+	return (d.head + i) % len(d.buf)
+}
+
+func (d *Deque[T]) grow() {
+	if d.len < len(d.buf) {
+		return
+	}
+	buf := make([]T, max(2*len(d.buf), 1))
+	for i := range d.len {
+		buf[i] = d.buf[d.slot(i)]
+	}
+	d.buf, d.head = buf, 0
+}
+
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.buf[d.slot(d.len)] = v
+	d.len++
+}
+
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = v
+	d.len++
+}
+
+// removes and returns the front element; ok is false when empty
+func (d *Deque[T]) PopFront() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	v = d.buf[d.head]
+	// clear the slot so it doesn't hold on to garbage
+	var zero T
+	d.buf[d.head] = zero
+	d.head = d.slot(1)
+	d.len--
+	return v, true
+}
+
+// removes and returns the back element; ok is false when empty
+func (d *Deque[T]) PopBack() (v T, ok bool) {
+	if d.len == 0 {
+		return v, false
+	}
+	last := d.slot(d.len - 1)
+	v = d.buf[last]
+	var zero T
+	d.buf[last] = zero
+	d.len--
+	return v, true
+}
+
+// At is the i'th element from the front; it panics if i is out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.len {
+		panic(fmt.Sprintf("deque index %v out of range [0:%v]", i, d.len))
+	}
+	return d.buf[d.slot(i)]
+}
+
+// All iterates front to back. The deque mustn't be changed while iterating.
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range d.len {
+			if !yield(i, d.buf[d.slot(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Ring is a fixed-size ring buffer for sliding windows: once full, each push drops the
+// oldest element. It never allocates after NewRing.
+type Ring[T any] struct {
+	buf []T
+	// index of the oldest element in buf
+	head int
+	len  int
+}
+
+// NewRing makes a ring holding up to capacity elements; capacity must be at least 1.
+func NewRing[T any](capacity int) *Ring[T] {
+	if capacity < 1 {
+		panic(fmt.Sprintf("ring capacity %v must be at least 1", capacity))
+	}
+	return &Ring[T]{buf: make([]T, capacity)}
+}
+
+func (r *Ring[T]) Len() int {
+	return r.len
+}
+
+func (r *Ring[T]) Cap() int {
+	return len(r.buf)
+}
+
+func (r *Ring[T]) Full() bool {
+	return r.len == len(r.buf)
+}
+
+// Push adds v as the newest element, returning the oldest if it had to be dropped to
+// make room.
+func (r *Ring[T]) Push(v T) (dropped T, ok bool) {
+	if r.Full() {
+		dropped, ok = r.buf[r.head], true
+		r.buf[r.head] = v
+		r.head = (r.head + 1) % len(r.buf)
+		return dropped, ok
+	}
+	r.buf[(r.head+r.len)%len(r.buf)] = v
+	r.len++
+	return dropped, false
+}
+
+// At is the i'th element, oldest first; it panics if i is out of range.
+func (r *Ring[T]) At(i int) T {
+	if i < 0 || i >= r.len {
+		panic(fmt.Sprintf("ring index %v out of range [0:%v]", i, r.len))
+	}
+	return r.buf[(r.head+i)%len(r.buf)]
+}
+
+// All iterates oldest to newest. The ring mustn't be changed while iterating.
+func (r *Ring[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := range r.len {
+			if !yield(i, r.buf[(r.head+i)%len(r.buf)]) {
+				return
+			}
+		}
+	}
+}
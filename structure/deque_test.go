@@ -0,0 +1,168 @@
+package structure
+
+import (
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func dequeValues[T any](d *Deque[T]) []T {
+	var values []T
+	for _, v := range d.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestDeque(t *testing.T) {
+	t.Run("PushBack() and PopFront() as a queue", func(t *testing.T) {
+		d := NewDeque[int](2)
+		for v := range 5 {
+			d.PushBack(v)
+		}
+
+		utils.CheckEqual(d.Len(), 5, t)
+		utils.CheckEqual(dequeValues(d), []int{0, 1, 2, 3, 4}, t)
+
+		v, ok := d.PopFront()
+		utils.CheckEqual(v, 0, t)
+		utils.CheckEqual(ok, true, t)
+		utils.CheckEqual(d.At(0), 1, t)
+	})
+
+	t.Run("PushFront() and PopBack()", func(t *testing.T) {
+		d := NewDeque[string](0)
+		d.PushFront("b")
+		d.PushFront("a")
+		d.PushBack("c")
+
+		utils.CheckEqual(dequeValues(d), []string{"a", "b", "c"}, t)
+
+		v, _ := d.PopBack()
+		utils.CheckEqual(v, "c", t)
+		v, _ = d.PopBack()
+		utils.CheckEqual(v, "b", t)
+		v, _ = d.PopFront()
+		utils.CheckEqual(v, "a", t)
+
+		_, ok := d.PopFront()
+		utils.CheckEqual(ok, false, t)
+		_, ok = d.PopBack()
+		utils.CheckEqual(ok, false, t)
+	})
+
+	t.Run("wraps around", func(t *testing.T) {
+		d := NewDeque[int](4)
+		for v := range 3 {
+			d.PushBack(v)
+		}
+		d.PopFront()
+		d.PopFront()
+		d.PushBack(3)
+		d.PushBack(4)
+		d.PushFront(1)
+
+		utils.CheckEqual(dequeValues(d), []int{1, 2, 3, 4}, t)
+		utils.CheckEqual(len(d.buf), 4, t)
+
+		// and grows while wrapped
+		d.PushBack(5)
+		utils.CheckEqual(dequeValues(d), []int{1, 2, 3, 4, 5}, t)
+		utils.CheckEqual(d.At(4), 5, t)
+	})
+
+	t.Run("At() out of range panics", func(t *testing.T) {
+		defer func() {
+			utils.CheckEqual(recover() != nil, true, t)
+		}()
+		d := NewDeque[int](1)
+		d.PushBack(1)
+		d.At(1)
+	})
+
+	t.Run("All() stops early", func(t *testing.T) {
+		d := NewDeque[int](1)
+		for v := range 5 {
+			d.PushBack(v)
+		}
+		count := 0
+		for i := range d.All() {
+			if i == 2 {
+				break
+			}
+			count++
+		}
+		utils.CheckEqual(count, 2, t)
+	})
+
+	t.Run("no allocations in steady state", func(t *testing.T) {
+		d := NewDeque[int](16)
+		allocs := testing.AllocsPerRun(100, func() {
+			for v := range 10 {
+				d.PushBack(v)
+				d.PushFront(v)
+			}
+			for range 10 {
+				d.PopFront()
+				d.PopBack()
+			}
+		})
+		utils.CheckEqual(allocs, 0.0, t)
+	})
+}
+
+func TestRing(t *testing.T) {
+	t.Run("Push() drops the oldest once full", func(t *testing.T) {
+		r := NewRing[int](3)
+		for v := range 3 {
+			_, ok := r.Push(v)
+			utils.CheckEqual(ok, false, t)
+		}
+		utils.CheckEqual(r.Full(), true, t)
+
+		dropped, ok := r.Push(3)
+		utils.CheckEqual(dropped, 0, t)
+		utils.CheckEqual(ok, true, t)
+
+		var values []int
+		for _, v := range r.All() {
+			values = append(values, v)
+		}
+		utils.CheckEqual(values, []int{1, 2, 3}, t)
+		utils.CheckEqual(r.At(0), 1, t)
+		utils.CheckEqual(r.At(2), 3, t)
+		utils.CheckEqual(r.Len(), 3, t)
+		utils.CheckEqual(r.Cap(), 3, t)
+	})
+
+	t.Run("sliding window sum", func(t *testing.T) {
+		r := NewRing[int](3)
+		sum := 0
+		var sums []int
+		for _, v := range []int{1, 2, 3, 4, 5} {
+			if dropped, ok := r.Push(v); ok {
+				sum -= dropped
+			}
+			sum += v
+			sums = append(sums, sum)
+		}
+		utils.CheckEqual(sums, []int{1, 3, 6, 9, 12}, t)
+	})
+
+	t.Run("bad capacity panics", func(t *testing.T) {
+		defer func() {
+			utils.CheckEqual(recover() != nil, true, t)
+		}()
+		NewRing[int](0)
+	})
+
+	t.Run("no allocations", func(t *testing.T) {
+		r := NewRing[int](8)
+		allocs := testing.AllocsPerRun(100, func() {
+			for v := range 20 {
+				r.Push(v)
+			}
+		})
+		utils.CheckEqual(allocs, 0.0, t)
+	})
+}
@@ -1,5 +1,13 @@
 package structure
 
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
 // Basic HashSet implementation
 type HashSet[T comparable] struct {
 	entries map[T]bool
@@ -53,3 +61,87 @@ func (h *HashSet[T]) Clone() *HashSet[T] {
 	return &hNew
 
 }
+
+// All iterates the elements in no particular order.
+func (h *HashSet[T]) All() iter.Seq[T] {
+	return maps.Keys(h.entries)
+}
+
+// elements in either set
+func (h *HashSet[T]) Union(o *HashSet[T]) *HashSet[T] {
+	union := h.Clone()
+	for e := range o.entries {
+		union.Add(e)
+	}
+	return union
+}
+
+// elements in both sets
+func (h *HashSet[T]) Intersect(o *HashSet[T]) *HashSet[T] {
+	small, large := h, o
+	if small.Size() > large.Size() {
+		small, large = large, small
+	}
+
+	intersection := NewHashSet[T]()
+	for e := range small.entries {
+		if large.Contains(e) {
+			intersection.Add(e)
+		}
+	}
+	return intersection
+}
+
+// elements in h but not o
+func (h *HashSet[T]) Difference(o *HashSet[T]) *HashSet[T] {
+	difference := NewHashSet[T]()
+	for e := range h.entries {
+		if !o.Contains(e) {
+			difference.Add(e)
+		}
+	}
+	return difference
+}
+
+// elements in exactly one of the sets
+func (h *HashSet[T]) SymmetricDifference(o *HashSet[T]) *HashSet[T] {
+	difference := h.Difference(o)
+	for e := range o.entries {
+		if !h.Contains(e) {
+			difference.Add(e)
+		}
+	}
+	return difference
+}
+
+// whether every element of h is in o
+func (h *HashSet[T]) IsSubset(o *HashSet[T]) bool {
+	if h.Size() > o.Size() {
+		return false
+	}
+	for e := range h.entries {
+		if !o.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *HashSet[T]) Equal(o *HashSet[T]) bool {
+	return h.Size() == o.Size() && h.IsSubset(o)
+}
+
+// the elements ordered by cmp
+func (h *HashSet[T]) Sorted(cmp func(a, b T) int) []T {
+	return slices.SortedFunc(h.All(), cmp)
+}
+
+// e.g. {1, 2, 3}; elements are ordered by how they print, so equal sets print the same
+func (h *HashSet[T]) String() string {
+	elements := make([]string, 0, h.Size())
+	for e := range h.entries {
+		elements = append(elements, fmt.Sprint(e))
+	}
+	slices.Sort(elements)
+	return "{" + strings.Join(elements, ", ") + "}"
+}
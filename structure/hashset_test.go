@@ -1,6 +1,8 @@
 package structure
 
 import (
+	"cmp"
+	"fmt"
 	"testing"
 
 	"iain.fyi/aoc2024/utils"
@@ -99,3 +101,63 @@ func TestHashset(t *testing.T) {
 		utils.CheckEqual(clone.entries[3], true, t)
 	})
 }
+
+func TestHashSetAlgebra(t *testing.T) {
+	set := func(es ...int) *HashSet[int] {
+		h := NewHashSet[int]()
+		h.AddAll(es...)
+		return h
+	}
+	a := set(1, 2, 3, 4)
+	b := set(3, 4, 5)
+
+	t.Run("All()", func(t *testing.T) {
+		utils.CheckSlicesHaveSameElements(utils.IterSeqToSlice(a.All()), []int{1, 2, 3, 4}, t)
+		utils.CheckEqual(len(utils.IterSeqToSlice(NewHashSet[int]().All())), 0, t)
+	})
+
+	t.Run("Union()", func(t *testing.T) {
+		utils.CheckEqual(a.Union(b).Equal(set(1, 2, 3, 4, 5)), true, t)
+		utils.CheckEqual(a.Size(), 4, t)
+	})
+
+	t.Run("Intersect()", func(t *testing.T) {
+		utils.CheckEqual(a.Intersect(b).Equal(set(3, 4)), true, t)
+		utils.CheckEqual(b.Intersect(a).Equal(set(3, 4)), true, t)
+		utils.CheckEqual(a.Intersect(set()).Size(), 0, t)
+	})
+
+	t.Run("Difference()", func(t *testing.T) {
+		utils.CheckEqual(a.Difference(b).Equal(set(1, 2)), true, t)
+		utils.CheckEqual(b.Difference(a).Equal(set(5)), true, t)
+	})
+
+	t.Run("SymmetricDifference()", func(t *testing.T) {
+		utils.CheckEqual(a.SymmetricDifference(b).Equal(set(1, 2, 5)), true, t)
+		utils.CheckEqual(a.SymmetricDifference(a).Size(), 0, t)
+	})
+
+	t.Run("IsSubset()", func(t *testing.T) {
+		utils.CheckEqual(set(3, 4).IsSubset(a), true, t)
+		utils.CheckEqual(set().IsSubset(a), true, t)
+		utils.CheckEqual(a.IsSubset(a), true, t)
+		utils.CheckEqual(b.IsSubset(a), false, t)
+	})
+
+	t.Run("Equal()", func(t *testing.T) {
+		utils.CheckEqual(a.Equal(set(4, 3, 2, 1)), true, t)
+		utils.CheckEqual(a.Equal(set(1, 2, 3)), false, t)
+		utils.CheckEqual(set(1, 2, 3).Equal(a), false, t)
+	})
+
+	t.Run("Sorted()", func(t *testing.T) {
+		utils.CheckEqual(a.Union(b).Sorted(cmp.Compare[int]), []int{1, 2, 3, 4, 5}, t)
+		utils.CheckEqual(b.Sorted(func(x, y int) int { return y - x }), []int{5, 4, 3}, t)
+	})
+
+	t.Run("String()", func(t *testing.T) {
+		utils.CheckEqual(b.String(), "{3, 4, 5}", t)
+		utils.CheckEqual(set().String(), "{}", t)
+		utils.CheckEqual(fmt.Sprint(b), "{3, 4, 5}", t)
+	})
+}
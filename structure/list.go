@@ -1,5 +1,18 @@
 package structure
 
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// List is a growable sequence, indexed from 0.
+//
+// A List is a small value holding a slice, so it behaves like one: assigning or passing a
+// List copies that slice header, not the elements, and the copy shares elements with the
+// original until one of them grows. Use Clone for an independent copy. Methods that
+// change a List take a pointer, so call them on a variable or field, not on a copy; the
+// zero List is empty and ready to use.
 type List[T any] struct {
 	data []T
 }
@@ -10,10 +23,100 @@ func NewList[T any]() List[T] {
 	}
 }
 
+// NewListOf makes a list holding a copy of the elements.
+func NewListOf[T any](elements ...T) List[T] {
+	return List[T]{data: append([]T{}, elements...)}
+}
+
 func (l *List[T]) Add(e T) {
 	l.data = append(l.data, e)
 }
 
+// AsSlice is the list's backing slice, not a copy: changes to either show in the other.
 func (l *List[T]) AsSlice() []T {
 	return l.data
 }
+
+func (l List[T]) Len() int {
+	return len(l.data)
+}
+
+// Get is the i'th element; it panics if i is out of range.
+func (l List[T]) Get(i int) T {
+	return l.data[i]
+}
+
+// Set replaces the i'th element; it panics if i is out of range.
+func (l *List[T]) Set(i int, e T) {
+	l.data[i] = e
+}
+
+// Insert puts e at index i, moving later elements up; i may be Len() to append. It panics
+// if i is out of range.
+func (l *List[T]) Insert(i int, e T) {
+	l.data = slices.Insert(l.data, i, e)
+}
+
+// RemoveAt removes and returns the i'th element, moving later elements down. It panics if
+// i is out of range.
+func (l *List[T]) RemoveAt(i int) T {
+	e := l.data[i]
+	l.data = slices.Delete(l.data, i, i+1)
+	return e
+}
+
+// Clone is a copy sharing nothing with l, though the elements themselves are copied
+// shallowly.
+func (l List[T]) Clone() List[T] {
+	return List[T]{data: slices.Clone(l.data)}
+}
+
+// EqualFunc reports whether the lists have the same length and eq holds for each pair of
+// elements.
+func (l List[T]) EqualFunc(other List[T], eq func(a, b T) bool) bool {
+	return slices.EqualFunc(l.data, other.data, eq)
+}
+
+// All iterates over indices and elements in order.
+func (l List[T]) All() iter.Seq2[int, T] {
+	return slices.All(l.data)
+}
+
+// Filter is a new list of the elements keep is true for, in order.
+func (l List[T]) Filter(keep func(T) bool) List[T] {
+	filtered := NewList[T]()
+	for _, e := range l.data {
+		if keep(e) {
+			filtered.Add(e)
+		}
+	}
+	return filtered
+}
+
+// String prints the elements like a slice, e.g. [1 2 3].
+func (l List[T]) String() string {
+	return fmt.Sprint(l.data)
+}
+
+// ListsEqual reports whether the lists have the same elements in the same order.
+func ListsEqual[T comparable](a, b List[T]) bool {
+	return slices.Equal(a.data, b.data)
+}
+
+// MapList is a new list of fn applied to each element, in order.
+func MapList[T, R any](l List[T], fn func(T) R) List[R] {
+	mapped := List[R]{data: make([]R, len(l.data))}
+	for i, e := range l.data {
+		mapped.data[i] = fn(e)
+	}
+	return mapped
+}
+
+// ReduceList folds the elements into one value, starting from initial.
+func ReduceList[T, A any](l List[T], initial A, fn func(A, T) A) A {
+	acc := initial
+	for _, e := range l.data {
+		acc = fn(acc, e)
+	}
+	return acc
+}
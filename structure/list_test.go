@@ -1,6 +1,7 @@
 package structure
 
 import (
+	"fmt"
 	"testing"
 
 	"iain.fyi/aoc2024/utils"
@@ -40,3 +41,97 @@ func TestList(t *testing.T) {
 	})
 
 }
+
+func TestListOperations(t *testing.T) {
+	t.Run("Get(), Set() and Len()", func(t *testing.T) {
+		l := NewListOf(1, 2, 3)
+		l.Set(1, 20)
+
+		utils.CheckEqual(l.Get(1), 20, t)
+		utils.CheckEqual(l.Len(), 3, t)
+	})
+
+	t.Run("Insert()", func(t *testing.T) {
+		l := NewListOf(1, 3)
+		l.Insert(1, 2)
+		l.Insert(0, 0)
+		l.Insert(4, 4)
+
+		utils.CheckEqual(l.AsSlice(), []int{0, 1, 2, 3, 4}, t)
+	})
+
+	t.Run("RemoveAt()", func(t *testing.T) {
+		l := NewListOf("a", "b", "c")
+
+		utils.CheckEqual(l.RemoveAt(1), "b", t)
+		utils.CheckEqual(l.AsSlice(), []string{"a", "c"}, t)
+	})
+
+	t.Run("Get() out of range panics", func(t *testing.T) {
+		defer func() {
+			utils.CheckEqual(recover() != nil, true, t)
+		}()
+		NewListOf(1).Get(1)
+	})
+
+	t.Run("copies share elements but clones don't", func(t *testing.T) {
+		l := NewListOf(1, 2, 3)
+		shared := l
+		clone := l.Clone()
+		l.Set(0, 10)
+
+		utils.CheckEqual(shared.Get(0), 10, t)
+		utils.CheckEqual(clone.Get(0), 1, t)
+
+		clone.Add(4)
+		utils.CheckEqual(l.Len(), 3, t)
+	})
+
+	t.Run("zero List is usable", func(t *testing.T) {
+		var l List[int]
+		l.Add(1)
+
+		utils.CheckEqual(l.Len(), 1, t)
+		utils.CheckEqual(l.Clone().Len(), 1, t)
+	})
+
+	t.Run("ListsEqual() and EqualFunc()", func(t *testing.T) {
+		a := NewListOf(1, 2, 3)
+
+		utils.CheckEqual(ListsEqual(a, NewListOf(1, 2, 3)), true, t)
+		utils.CheckEqual(ListsEqual(a, NewListOf(1, 2)), false, t)
+		utils.CheckEqual(ListsEqual(NewList[int](), List[int]{}), true, t)
+
+		sameParity := func(x, y int) bool { return x%2 == y%2 }
+		utils.CheckEqual(a.EqualFunc(NewListOf(3, 4, 5), sameParity), true, t)
+	})
+
+	t.Run("All()", func(t *testing.T) {
+		var indices, elements []int
+		for i, e := range NewListOf(5, 6, 7).All() {
+			indices = append(indices, i)
+			elements = append(elements, e)
+		}
+
+		utils.CheckEqual(indices, []int{0, 1, 2}, t)
+		utils.CheckEqual(elements, []int{5, 6, 7}, t)
+	})
+
+	t.Run("Filter(), MapList() and ReduceList()", func(t *testing.T) {
+		l := NewListOf(1, 2, 3, 4)
+
+		evens := l.Filter(func(e int) bool { return e%2 == 0 })
+		utils.CheckEqual(evens.AsSlice(), []int{2, 4}, t)
+
+		labels := MapList(l, func(e int) string { return fmt.Sprintf("#%v", e) })
+		utils.CheckEqual(labels.AsSlice(), []string{"#1", "#2", "#3", "#4"}, t)
+
+		sum := ReduceList(l, 0, func(acc, e int) int { return acc + e })
+		utils.CheckEqual(sum, 10, t)
+	})
+
+	t.Run("String()", func(t *testing.T) {
+		utils.CheckEqual(NewListOf(1, 2, 3).String(), "[1 2 3]", t)
+		utils.CheckEqual(fmt.Sprint(NewList[string]()), "[]", t)
+	})
+}
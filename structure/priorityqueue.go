@@ -0,0 +1,193 @@
+package structure
+
+// Handle refers to a value in a PriorityQueue, so its priority can be changed later.
+type Handle[T any] struct {
+	value T
+	// position in the heap, or -1 once the value has left the queue
+	index int
+}
+
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// whether the value is still queued
+func (h *Handle[T]) Queued() bool {
+	return h.index >= 0
+}
+
+// PriorityQueue is a binary min-heap: Pop gives the value less puts first.
+type PriorityQueue[T any] struct {
+	heap []*Handle[T]
+	less func(a, b T) bool
+}
+
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.heap)
+}
+
+func (pq *PriorityQueue[T]) Push(v T) *Handle[T] {
+	h := &Handle[T]{value: v, index: len(pq.heap)}
+	pq.heap = append(pq.heap, h)
+	pq.up(h.index)
+	return h
+}
+
+// the first value, without removing it; ok is false when empty
+func (pq *PriorityQueue[T]) Peek() (v T, ok bool) {
+	if len(pq.heap) == 0 {
+		return v, false
+	}
+	return pq.heap[0].value, true
+}
+
+// removes and returns the first value; ok is false when empty
+func (pq *PriorityQueue[T]) Pop() (v T, ok bool) {
+	if len(pq.heap) == 0 {
+		return v, false
+	}
+	return pq.removeAt(0).value, true
+}
+
+// Update replaces a queued value, e.g. with a lower cost (decrease-key), and moves it
+// to its new place. It does nothing if the value has already left the queue.
+func (pq *PriorityQueue[T]) Update(h *Handle[T], v T) {
+	if !h.Queued() {
+		return
+	}
+	h.value = v
+	pq.fix(h.index)
+}
+
+// Remove takes a queued value out of the queue; it does nothing if it's already gone.
+func (pq *PriorityQueue[T]) Remove(h *Handle[T]) {
+	if !h.Queued() {
+		return
+	}
+	pq.removeAt(h.index)
+}
+
+func (pq *PriorityQueue[T]) removeAt(i int) *Handle[T] {
+	last := len(pq.heap) - 1
+	removed := pq.heap[i]
+	pq.swap(i, last)
+	pq.heap[last] = nil
+	pq.heap = pq.heap[:last]
+	if i < last {
+		pq.fix(i)
+	}
+	removed.index = -1
+	return removed
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.heap[i], pq.heap[j] = pq.heap[j], pq.heap[i]
+	pq.heap[i].index = i
+	pq.heap[j].index = j
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.heap[i].value, pq.heap[parent].value) {
+			return
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// reports whether the value moved
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start := i
+	for {
+		smallest := i
+		if left := 2*i + 1; left < len(pq.heap) && pq.less(pq.heap[left].value, pq.heap[smallest].value) {
+			smallest = left
+		}
+		if right := 2*i + 2; right < len(pq.heap) && pq.less(pq.heap[right].value, pq.heap[smallest].value) {
+			smallest = right
+		}
+		if smallest == i {
+			return i > start
+		}
+		pq.swap(i, smallest)
+		i = smallest
+	}
+}
+
+type keyed[K comparable, P any] struct {
+	key      K
+	priority P
+}
+
+// IndexedPriorityQueue is a PriorityQueue of keys, each queued at most once, which can be
+// looked up and reprioritised by key, e.g. vertices in Dijkstra's algorithm.
+type IndexedPriorityQueue[K comparable, P any] struct {
+	pq      *PriorityQueue[keyed[K, P]]
+	handles map[K]*Handle[keyed[K, P]]
+}
+
+func NewIndexedPriorityQueue[K comparable, P any](less func(a, b P) bool) *IndexedPriorityQueue[K, P] {
+	return &IndexedPriorityQueue[K, P]{
+		pq:      NewPriorityQueue(func(a, b keyed[K, P]) bool { return less(a.priority, b.priority) }),
+		handles: make(map[K]*Handle[keyed[K, P]]),
+	}
+}
+
+func (ipq *IndexedPriorityQueue[K, P]) Len() int {
+	return ipq.pq.Len()
+}
+
+// Set queues the key with the priority, or changes its priority if it's already queued.
+func (ipq *IndexedPriorityQueue[K, P]) Set(key K, priority P) {
+	if h, ok := ipq.handles[key]; ok {
+		ipq.pq.Update(h, keyed[K, P]{key, priority})
+		return
+	}
+	ipq.handles[key] = ipq.pq.Push(keyed[K, P]{key, priority})
+}
+
+func (ipq *IndexedPriorityQueue[K, P]) Contains(key K) bool {
+	_, ok := ipq.handles[key]
+	return ok
+}
+
+// priority of a queued key
+func (ipq *IndexedPriorityQueue[K, P]) Priority(key K) (p P, ok bool) {
+	h, ok := ipq.handles[key]
+	if !ok {
+		return p, false
+	}
+	return h.value.priority, true
+}
+
+func (ipq *IndexedPriorityQueue[K, P]) Peek() (key K, priority P, ok bool) {
+	e, ok := ipq.pq.Peek()
+	return e.key, e.priority, ok
+}
+
+func (ipq *IndexedPriorityQueue[K, P]) Pop() (key K, priority P, ok bool) {
+	e, ok := ipq.pq.Pop()
+	if ok {
+		delete(ipq.handles, e.key)
+	}
+	return e.key, e.priority, ok
+}
+
+func (ipq *IndexedPriorityQueue[K, P]) Remove(key K) {
+	if h, ok := ipq.handles[key]; ok {
+		ipq.pq.Remove(h)
+		delete(ipq.handles, key)
+	}
+}
@@ -0,0 +1,226 @@
+package structure
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func drain[T any](pq *PriorityQueue[T]) []T {
+	var out []T
+	for pq.Len() > 0 {
+		v, _ := pq.Pop()
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestPriorityQueue(t *testing.T) {
+	t.Run("Push() and Pop()", func(t *testing.T) {
+		pq := NewPriorityQueue(intLess)
+		for _, v := range []int{5, 1, 4, 1, 3, 9, 2} {
+			pq.Push(v)
+		}
+
+		utils.CheckEqual(pq.Len(), 7, t)
+		utils.CheckEqual(drain(pq), []int{1, 1, 2, 3, 4, 5, 9}, t)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		pq := NewPriorityQueue(intLess)
+		_, ok := pq.Pop()
+		utils.CheckEqual(ok, false, t)
+		_, ok = pq.Peek()
+		utils.CheckEqual(ok, false, t)
+	})
+
+	t.Run("Peek()", func(t *testing.T) {
+		pq := NewPriorityQueue(func(a, b string) bool { return len(a) < len(b) })
+		pq.Push("ccc")
+		pq.Push("a")
+		pq.Push("bb")
+
+		v, ok := pq.Peek()
+		utils.CheckEqual(v, "a", t)
+		utils.CheckEqual(ok, true, t)
+		utils.CheckEqual(pq.Len(), 3, t)
+	})
+
+	t.Run("Update()", func(t *testing.T) {
+		pq := NewPriorityQueue(intLess)
+		handles := make(map[int]*Handle[int])
+		for _, v := range []int{10, 20, 30, 40} {
+			handles[v] = pq.Push(v)
+		}
+
+		pq.Update(handles[40], 5)
+		pq.Update(handles[10], 35)
+		utils.CheckEqual(handles[40].Value(), 5, t)
+		utils.CheckEqual(drain(pq), []int{5, 20, 30, 35}, t)
+
+		// popped values can't be updated
+		utils.CheckEqual(handles[20].Queued(), false, t)
+		pq.Update(handles[20], 1)
+		utils.CheckEqual(pq.Len(), 0, t)
+	})
+
+	t.Run("Remove()", func(t *testing.T) {
+		pq := NewPriorityQueue(intLess)
+		var handles []*Handle[int]
+		for v := range 10 {
+			handles = append(handles, pq.Push(v))
+		}
+
+		pq.Remove(handles[0])
+		pq.Remove(handles[5])
+		pq.Remove(handles[9])
+		pq.Remove(handles[5])
+		utils.CheckEqual(drain(pq), []int{1, 2, 3, 4, 6, 7, 8}, t)
+	})
+
+	t.Run("random operations keep heap order", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		pq := NewPriorityQueue(intLess)
+		var handles []*Handle[int]
+
+		for range 2000 {
+			switch rng.Intn(4) {
+			case 0, 1:
+				handles = append(handles, pq.Push(rng.Intn(1000)))
+			case 2:
+				pq.Pop()
+			case 3:
+				if len(handles) > 0 {
+					pq.Update(handles[rng.Intn(len(handles))], rng.Intn(1000))
+				}
+			}
+		}
+
+		var want []int
+		for _, h := range handles {
+			if h.Queued() {
+				want = append(want, h.Value())
+			}
+		}
+		slices.Sort(want)
+		utils.CheckEqual(drain(pq), want, t)
+	})
+}
+
+func TestIndexedPriorityQueue(t *testing.T) {
+	t.Run("Set() inserts and reprioritises", func(t *testing.T) {
+		ipq := NewIndexedPriorityQueue[string](intLess)
+		ipq.Set("a", 5)
+		ipq.Set("b", 3)
+		ipq.Set("c", 4)
+		ipq.Set("a", 1)
+
+		utils.CheckEqual(ipq.Len(), 3, t)
+		p, ok := ipq.Priority("a")
+		utils.CheckEqual(p, 1, t)
+		utils.CheckEqual(ok, true, t)
+
+		key, priority, _ := ipq.Peek()
+		utils.CheckEqual(key, "a", t)
+		utils.CheckEqual(priority, 1, t)
+
+		var order []string
+		for ipq.Len() > 0 {
+			key, _, _ := ipq.Pop()
+			order = append(order, key)
+		}
+		utils.CheckEqual(order, []string{"a", "b", "c"}, t)
+		utils.CheckEqual(ipq.Contains("a"), false, t)
+	})
+
+	t.Run("Remove()", func(t *testing.T) {
+		ipq := NewIndexedPriorityQueue[int](intLess)
+		ipq.Set(1, 10)
+		ipq.Set(2, 20)
+		ipq.Remove(1)
+		ipq.Remove(3)
+
+		utils.CheckEqual(ipq.Contains(1), false, t)
+		_, ok := ipq.Priority(1)
+		utils.CheckEqual(ok, false, t)
+
+		key, _, ok := ipq.Pop()
+		utils.CheckEqual(key, 2, t)
+		utils.CheckEqual(ok, true, t)
+
+		_, _, ok = ipq.Pop()
+		utils.CheckEqual(ok, false, t)
+	})
+
+	t.Run("Dijkstra", func(t *testing.T) {
+		// 0 -> 1 (4), 0 -> 2 (1), 2 -> 1 (2), 1 -> 3 (1), 2 -> 3 (5)
+		edges := map[int][][2]int{
+			0: {{1, 4}, {2, 1}},
+			1: {{3, 1}},
+			2: {{1, 2}, {3, 5}},
+		}
+
+		dist := map[int]int{0: 0}
+		ipq := NewIndexedPriorityQueue[int](intLess)
+		ipq.Set(0, 0)
+		for ipq.Len() > 0 {
+			v, d, _ := ipq.Pop()
+			for _, e := range edges[v] {
+				if old, seen := dist[e[0]]; !seen || d+e[1] < old {
+					dist[e[0]] = d + e[1]
+					ipq.Set(e[0], d+e[1])
+				}
+			}
+		}
+
+		utils.CheckEqual(dist, map[int]int{0: 0, 1: 3, 2: 1, 3: 4}, t)
+	})
+}
+
+// a shortest-path style workload: pop the cheapest, push a few more
+func benchmarkWorkload(push func(int), pop func() int, size func() int) {
+	rng := rand.New(rand.NewSource(1))
+	for range 100 {
+		push(rng.Intn(1000))
+	}
+	for range 2000 {
+		cost := pop()
+		for range rng.Intn(3) {
+			push(cost + rng.Intn(100))
+		}
+		if size() == 0 {
+			push(rng.Intn(1000))
+		}
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	for range b.N {
+		pq := NewPriorityQueue(intLess)
+		benchmarkWorkload(
+			func(v int) { pq.Push(v) },
+			func() int { v, _ := pq.Pop(); return v },
+			pq.Len)
+	}
+}
+
+// what day-16 does: sort the whole slice before taking the first
+func BenchmarkSortEachStep(b *testing.B) {
+	for range b.N {
+		var queue []int
+		benchmarkWorkload(
+			func(v int) { queue = append(queue, v) },
+			func() int {
+				sort.SliceStable(queue, func(i, j int) bool { return queue[i] < queue[j] })
+				v := queue[0]
+				queue = queue[1:]
+				return v
+			},
+			func() int { return len(queue) })
+	}
+}
@@ -0,0 +1,183 @@
+package structure
+
+import "iter"
+
+// DenseUnionFind is a disjoint-set over the ints 0..n-1, with path compression and union
+// by rank. It suits grids, numbering cells y*width + x.
+type DenseUnionFind struct {
+	parent []int
+	rank   []int
+	// component size, kept up to date on roots only
+	size []int
+	// number of components
+	count int
+}
+
+func NewDenseUnionFind(n int) *DenseUnionFind {
+	uf := &DenseUnionFind{}
+	for range n {
+		uf.add()
+	}
+	return uf
+}
+
+// add appends a new singleton, returning its index
+func (uf *DenseUnionFind) add() int {
+	i := len(uf.parent)
+	uf.parent = append(uf.parent, i)
+	uf.rank = append(uf.rank, 0)
+	uf.size = append(uf.size, 1)
+	uf.count++
+	return i
+}
+
+// number of elements
+func (uf *DenseUnionFind) Len() int {
+	return len(uf.parent)
+}
+
+// number of components
+func (uf *DenseUnionFind) Count() int {
+	return uf.count
+}
+
+// Find is the root of i's component, flattening the path to it on the way.
+func (uf *DenseUnionFind) Find(i int) int {
+	root := i
+	for uf.parent[root] != root {
+		root = uf.parent[root]
+	}
+	for uf.parent[i] != root {
+		uf.parent[i], i = root, uf.parent[i]
+	}
+	return root
+}
+
+// Union joins the components of a and b, reporting whether they were separate.
+func (uf *DenseUnionFind) Union(a, b int) bool {
+	a, b = uf.Find(a), uf.Find(b)
+	if a == b {
+		return false
+	}
+	if uf.rank[a] < uf.rank[b] {
+		a, b = b, a
+	}
+	uf.parent[b] = a
+	uf.size[a] += uf.size[b]
+	if uf.rank[a] == uf.rank[b] {
+		uf.rank[a]++
+	}
+	uf.count--
+	return true
+}
+
+func (uf *DenseUnionFind) Connected(a, b int) bool {
+	return uf.Find(a) == uf.Find(b)
+}
+
+// number of elements in i's component
+func (uf *DenseUnionFind) ComponentSize(i int) int {
+	return uf.size[uf.Find(i)]
+}
+
+// Components yields each component's elements in ascending order, components ordered by
+// their smallest element.
+func (uf *DenseUnionFind) Components() iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		members := make(map[int][]int, uf.count)
+		var roots []int
+		for i := range uf.parent {
+			root := uf.Find(i)
+			if _, ok := members[root]; !ok {
+				roots = append(roots, root)
+			}
+			members[root] = append(members[root], i)
+		}
+		for _, root := range roots {
+			if !yield(members[root]) {
+				return
+			}
+		}
+	}
+}
+
+// UnionFind is a disjoint-set over any comparable values, added as they're first seen. A
+// value never added is a component on its own.
+type UnionFind[T comparable] struct {
+	index  map[T]int
+	values []T
+	dense  *DenseUnionFind
+}
+
+func NewUnionFind[T comparable]() *UnionFind[T] {
+	return &UnionFind[T]{index: make(map[T]int), dense: NewDenseUnionFind(0)}
+}
+
+// Add puts v in as its own component if it isn't there already.
+func (uf *UnionFind[T]) Add(v T) {
+	uf.indexOf(v)
+}
+
+func (uf *UnionFind[T]) indexOf(v T) int {
+	if i, ok := uf.index[v]; ok {
+		return i
+	}
+	i := uf.dense.add()
+	uf.index[v] = i
+	uf.values = append(uf.values, v)
+	return i
+}
+
+// number of values added
+func (uf *UnionFind[T]) Len() int {
+	return len(uf.values)
+}
+
+// number of components among the values added
+func (uf *UnionFind[T]) Count() int {
+	return uf.dense.Count()
+}
+
+// Find is the value representing v's component.
+func (uf *UnionFind[T]) Find(v T) T {
+	i, ok := uf.index[v]
+	if !ok {
+		return v
+	}
+	return uf.values[uf.dense.Find(i)]
+}
+
+// Union joins the components of a and b, adding either if needed, and reports whether
+// they were separate.
+func (uf *UnionFind[T]) Union(a, b T) bool {
+	return uf.dense.Union(uf.indexOf(a), uf.indexOf(b))
+}
+
+func (uf *UnionFind[T]) Connected(a, b T) bool {
+	return uf.Find(a) == uf.Find(b)
+}
+
+// number of values in v's component
+func (uf *UnionFind[T]) ComponentSize(v T) int {
+	i, ok := uf.index[v]
+	if !ok {
+		return 1
+	}
+	return uf.dense.ComponentSize(i)
+}
+
+// Components yields each component's values in the order they were added, components
+// ordered by their first value.
+func (uf *UnionFind[T]) Components() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for indices := range uf.dense.Components() {
+			component := make([]T, len(indices))
+			for j, i := range indices {
+				component[j] = uf.values[i]
+			}
+			if !yield(component) {
+				return
+			}
+		}
+	}
+}
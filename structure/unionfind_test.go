@@ -0,0 +1,121 @@
+package structure
+
+import (
+	"testing"
+
+	"iain.fyi/aoc2024/utils"
+)
+
+func TestDenseUnionFind(t *testing.T) {
+	t.Run("Union() joins components", func(t *testing.T) {
+		uf := NewDenseUnionFind(6)
+		utils.CheckEqual(uf.Count(), 6, t)
+
+		utils.CheckEqual(uf.Union(0, 1), true, t)
+		utils.CheckEqual(uf.Union(1, 2), true, t)
+		utils.CheckEqual(uf.Union(2, 0), false, t)
+		uf.Union(4, 5)
+
+		utils.CheckEqual(uf.Count(), 3, t)
+		utils.CheckEqual(uf.Connected(0, 2), true, t)
+		utils.CheckEqual(uf.Connected(0, 3), false, t)
+		utils.CheckEqual(uf.Find(2), uf.Find(0), t)
+		utils.CheckEqual(uf.ComponentSize(1), 3, t)
+		utils.CheckEqual(uf.ComponentSize(3), 1, t)
+		utils.CheckEqual(uf.Len(), 6, t)
+	})
+
+	t.Run("Components()", func(t *testing.T) {
+		uf := NewDenseUnionFind(5)
+		uf.Union(4, 1)
+		uf.Union(0, 3)
+		uf.Union(3, 4)
+
+		var got [][]int
+		for c := range uf.Components() {
+			got = append(got, c)
+		}
+		utils.CheckEqual(got, [][]int{{0, 1, 3, 4}, {2}}, t)
+	})
+
+	t.Run("grid flood fill", func(t *testing.T) {
+		grid := []string{
+			"##.",
+			"..#",
+			"#.#",
+		}
+		width := len(grid[0])
+		uf := NewDenseUnionFind(width * len(grid))
+		for y, row := range grid {
+			for x := range row {
+				if x+1 < width && row[x] == row[x+1] {
+					uf.Union(y*width+x, y*width+x+1)
+				}
+				if y+1 < len(grid) && row[x] == grid[y+1][x] {
+					uf.Union(y*width+x, (y+1)*width+x)
+				}
+			}
+		}
+
+		utils.CheckEqual(uf.Count(), 5, t)
+		utils.CheckEqual(uf.ComponentSize(3), 3, t)
+		utils.CheckEqual(uf.Connected(5, 8), true, t)
+	})
+
+	t.Run("long chain stays shallow", func(t *testing.T) {
+		uf := NewDenseUnionFind(1000)
+		for i := range 999 {
+			uf.Union(i, i+1)
+		}
+		root := uf.Find(0)
+		for i := range 1000 {
+			uf.Find(i)
+			utils.CheckEqual(uf.parent[i], root, t)
+		}
+		utils.CheckEqual(uf.ComponentSize(500), 1000, t)
+	})
+}
+
+func TestUnionFind(t *testing.T) {
+	t.Run("values are added as they're seen", func(t *testing.T) {
+		uf := NewUnionFind[string]()
+		uf.Union("a", "b")
+		uf.Union("c", "d")
+		uf.Add("e")
+		uf.Add("a")
+
+		utils.CheckEqual(uf.Len(), 5, t)
+		utils.CheckEqual(uf.Count(), 3, t)
+		utils.CheckEqual(uf.Connected("a", "b"), true, t)
+		utils.CheckEqual(uf.Connected("a", "c"), false, t)
+		utils.CheckEqual(uf.ComponentSize("d"), 2, t)
+
+		utils.CheckEqual(uf.Union("b", "d"), true, t)
+		utils.CheckEqual(uf.Find("a"), uf.Find("c"), t)
+		utils.CheckEqual(uf.ComponentSize("a"), 4, t)
+	})
+
+	t.Run("unknown values are alone", func(t *testing.T) {
+		uf := NewUnionFind[int]()
+		uf.Union(1, 2)
+
+		utils.CheckEqual(uf.Find(7), 7, t)
+		utils.CheckEqual(uf.Connected(7, 7), true, t)
+		utils.CheckEqual(uf.Connected(7, 1), false, t)
+		utils.CheckEqual(uf.ComponentSize(7), 1, t)
+		utils.CheckEqual(uf.Len(), 2, t)
+	})
+
+	t.Run("Components()", func(t *testing.T) {
+		uf := NewUnionFind[rune]()
+		for _, pair := range []string{"xy", "pq", "zx", "rr"} {
+			uf.Union(rune(pair[0]), rune(pair[1]))
+		}
+
+		var got []string
+		for c := range uf.Components() {
+			got = append(got, string(c))
+		}
+		utils.CheckEqual(got, []string{"xyz", "pq", "r"}, t)
+	})
+}